@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+type accountMenuItem struct {
+	MenuItem *systray.MenuItem
+	Balance  *systray.MenuItem
+	Hotspots *systray.MenuItem
+	Rewards  []*systray.MenuItem // one row per reward window
+	Explorer *systray.MenuItem
+}
+
+// newAccountMenuItem adds an account row to parent, or to the top level of
+// the menu if parent is nil
+func newAccountMenuItem(parent *systray.MenuItem, windows []rewardWindow) *accountMenuItem {
+	var item *systray.MenuItem
+	if parent != nil {
+		item = parent.AddSubMenuItem("Loading...", "")
+	} else {
+		item = systray.AddMenuItem("Loading...", "")
+	}
+	row := &accountMenuItem{
+		MenuItem: item,
+		Balance:  item.AddSubMenuItem("Loading...", "Wallet balance"),
+		Hotspots: item.AddSubMenuItem("Loading...", "Hotspots owned"),
+	}
+	row.Rewards = addRewardRows(item, windows)
+	row.Explorer = item.AddSubMenuItem("Loading...", "Open account in the explorer")
+	return row
+}
+
+// GetAccounts fetches the wallet balance of every tracked account
+func (cfg *config) GetAccounts(ctx context.Context) error {
+	accounts := make(map[string]account)
+	for _, addr := range cfg.AccountAddresses {
+		resp, err := getAccount(ctx, addr)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			handleSoftError(err, "Failed to get account balance")
+			continue
+		}
+
+		accounts[addr] = resp.Data
+		if err := cfg.sleep(ctx); err != nil {
+			return err
+		}
+	}
+
+	cfg.mu.Lock()
+	cfg.Accounts = accounts
+	cfg.mu.Unlock()
+	return nil
+}
+
+// addAccountMenuItems adds a row for every tracked account below the hotspots
+func (cfg *config) addAccountMenuItems(parent *systray.MenuItem) {
+	if parent == nil && len(cfg.AccountMenuItems) == 0 && len(cfg.AccountAddresses) > 0 {
+		systray.AddSeparator()
+	}
+	for len(cfg.AccountMenuItems) < len(cfg.AccountAddresses) {
+		cfg.AccountMenuItems = append(cfg.AccountMenuItems, newAccountMenuItem(parent, cfg.RewardWindows))
+	}
+}
+
+func (cfg *config) updateAccounts(now time.Time) {
+	for i, addr := range cfg.AccountAddresses {
+		// Accounts without a row yet are shown once addMenuItems adds one
+		if i >= len(cfg.AccountMenuItems) {
+			break
+		}
+		row := cfg.AccountMenuItems[i]
+
+		// Rewards of the hotspots owned by the account
+		var orders []sortOrder
+		owned := 0
+		for _, hs := range cfg.HsMap {
+			if hs.Owner == addr {
+				owned++
+			}
+		}
+		for _, order := range cfg.HsSort {
+			if cfg.HsMap[order.Name].Owner == addr {
+				orders = append(orders, order)
+			}
+		}
+
+		onlineStatus := cfg.groupStatus(orders)
+		balance, found := cfg.Accounts[addr]
+		if found {
+			row.MenuItem.SetTitle(fmt.Sprintf("%s - Account %s", cfg.rewardToString(balance.Balance), shortAddress(addr)))
+			row.Balance.SetTitle(fmt.Sprintf("Balance: %s", cfg.rewardToString(balance.Balance)))
+		} else {
+			row.MenuItem.SetTitle(fmt.Sprintf("n/a - Account %s", shortAddress(addr)))
+			row.Balance.SetTitle("Balance: n/a")
+		}
+		setStatus(row.MenuItem, onlineStatus, cfg.GroupDiff(orders, 1).Diff())
+		row.Hotspots.SetTitle(fmt.Sprintf("Hotspots: %d", owned))
+
+		row.Rewards = syncRewardRows(row.MenuItem, row.Rewards, cfg.RewardWindows)
+		for j, w := range cfg.RewardWindows {
+			d := cfg.GroupDiff(orders, w.Length(now))
+			setStatus(row.Rewards[j], onlineStatus, d.Diff())
+			row.Rewards[j].SetTitle(cfg.rewardDiffString(w.Label(), d))
+		}
+
+		row.Explorer.SetTitle("Open explorer...")
+		events.Register(row.Explorer, openURLAction(cfg.accountURL(addr)))
+		row.MenuItem.Show()
+	}
+
+	// Hide rows of accounts no longer in the config
+	for i := len(cfg.AccountAddresses); i < len(cfg.AccountMenuItems); i++ {
+		events.Unregister(cfg.AccountMenuItems[i].Explorer)
+		cfg.AccountMenuItems[i].MenuItem.Hide()
+	}
+}
+
+// shortAddress shortens an address for display, the explorer link has it in full
+func shortAddress(addr string) string {
+	if len(addr) <= 12 {
+		return addr
+	}
+	return addr[:6] + "..." + addr[len(addr)-6:]
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+const (
+	activityDays     = 8  // the last 7 days and the day before them for the trend
+	activityMinutes  = 60 // minimum time between activity fetches
+	maxActivityPages = 50 // stop paging activity after this many pages
+)
+
+// activityEvent is what a hotspot did in one proof of coverage receipt
+type activityEvent struct {
+	Time    time.Time
+	Beacons int
+	Valid   int // valid witnesses of other hotspots' beacons
+	Invalid int
+}
+
+// activityCount sums activity events over a window
+type activityCount struct {
+	Beacons int
+	Valid   int
+	Invalid int
+}
+
+// hotspotActivity is the recent proof of coverage activity of a hotspot
+type hotspotActivity struct {
+	Witnesses int // hotspots that recently witnessed this one
+	Events    []activityEvent
+}
+
+type activityMenuItem struct {
+	MenuItem *systray.MenuItem
+	Recent   *systray.MenuItem
+	Week     *systray.MenuItem
+}
+
+func newActivityMenuItem(parent *systray.MenuItem) *activityMenuItem {
+	item := parent.AddSubMenuItem("Loading activity...", "Witnesses and beacons")
+	return &activityMenuItem{
+		MenuItem: item,
+		Recent:   item.AddSubMenuItem("Loading...", "Activity over the last day"),
+		Week:     item.AddSubMenuItem("Loading...", "Activity over the last 7 days"),
+	}
+}
+
+// fetchActivity gets the witnesses of a hotspot and its proof of coverage
+// receipts since since
+func fetchActivity(ctx context.Context, address string, since time.Time) (hotspotActivity, error) {
+	var activity hotspotActivity
+	witnesses, err := getHotspotWitnesses(ctx, address)
+	if err != nil {
+		return activity, err
+	}
+	activity.Witnesses = len(witnesses.Data)
+
+	// The API may return empty pages with a cursor while it scans blocks
+	cursor := ""
+	for page := 0; page < maxActivityPages; page++ {
+		resp, err := getHotspotActivity(ctx, address, since, cursor)
+		if err != nil {
+			return activity, err
+		}
+		for _, txn := range resp.Data {
+			if event := receiptEvent(address, txn); event.Beacons+event.Valid+event.Invalid > 0 {
+				activity.Events = append(activity.Events, event)
+			}
+		}
+
+		if resp.Cursor == "" {
+			break
+		}
+		cursor = resp.Cursor
+	}
+	return activity, nil
+}
+
+// receiptEvent counts the beacons and witnesses of address in a receipt
+func receiptEvent(address string, txn transaction) activityEvent {
+	event := activityEvent{Time: time.Unix(txn.Time, 0)}
+	for _, elem := range txn.Path {
+		if elem.Challengee == address && elem.Receipt != nil {
+			event.Beacons++
+		}
+		for _, w := range elem.Witnesses {
+			if w.Gateway != address {
+				continue
+			}
+			if w.IsValid {
+				event.Valid++
+			} else {
+				event.Invalid++
+			}
+		}
+	}
+	return event
+}
+
+// Count sums the events from up to but not including to
+func (a hotspotActivity) Count(from time.Time, to time.Time) activityCount {
+	var count activityCount
+	for _, e := range a.Events {
+		if e.Time.Before(from) || !e.Time.Before(to) {
+			continue
+		}
+		count.Beacons += e.Beacons
+		count.Valid += e.Valid
+		count.Invalid += e.Invalid
+	}
+	return count
+}
+
+// GetHotspotActivity fetches witnesses and activity of every visible hotspot,
+// at most once every activityMinutes
+func (cfg *config) GetHotspotActivity(ctx context.Context) error {
+	now := cfg.Clock.Now()
+	if now.Sub(cfg.ActivityFetched) < activityMinutes*time.Minute {
+		return nil
+	}
+	since := cfg.Clock.WindowStart(now, activityDays)
+
+	activities := make(map[string]hotspotActivity)
+	for name, hs := range cfg.HsMap {
+		if cfg.Hotspots[hs.Address].Hidden {
+			continue
+		}
+
+		activity, err := fetchActivity(ctx, hs.Address, since)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			handleSoftError(err, "Failed to get hotspot activity")
+			continue
+		}
+		activities[name] = activity
+		if err := cfg.sleep(ctx); err != nil {
+			return err
+		}
+	}
+
+	cfg.mu.Lock()
+	cfg.HsActivity = activities
+	cfg.ActivityFetched = now
+	cfg.mu.Unlock()
+	return nil
+}
+
+// ActivityCount sums the activity of a hotspot over the last days
+func (cfg *config) ActivityCount(name string, days int, now time.Time) activityCount {
+	return cfg.HsActivity[name].Count(cfg.Clock.WindowStart(now, days), now)
+}
+
+// ActivityTrend compares the valid witnesses and beacons of the last day with
+// the daily average of the 7 days before it
+func (cfg *config) ActivityTrend(name string, now time.Time) string {
+	dayStart := cfg.Clock.WindowStart(now, 1)
+	recent := cfg.HsActivity[name].Count(dayStart, now)
+	before := cfg.HsActivity[name].Count(dayStart.AddDate(0, 0, -7), dayStart)
+
+	current := float64(recent.Valid + recent.Beacons)
+	average := float64(before.Valid+before.Beacons) / 7
+	switch {
+	case current > average*1.2:
+		return "↑"
+	case current < average*0.8:
+		return "↓"
+	default:
+		return "→"
+	}
+}
+
+func activityCountString(label string, c activityCount) string {
+	return fmt.Sprintf("%s: %d beacons, %d valid / %d invalid witnesses", label, c.Beacons, c.Valid, c.Invalid)
+}
+
+// activityTitle summarizes the activity of a hotspot on one line
+func (cfg *config) activityTitle(name string, now time.Time) string {
+	activity, found := cfg.HsActivity[name]
+	if !found {
+		return "Activity: n/a"
+	}
+	return fmt.Sprintf("Activity: %d witnesses %s", activity.Witnesses, cfg.ActivityTrend(name, now))
+}
+
+func (cfg *config) updateActivity(item *activityMenuItem, name string, now time.Time) {
+	item.MenuItem.SetTitle(cfg.activityTitle(name, now))
+	if _, found := cfg.HsActivity[name]; !found {
+		item.Recent.Hide()
+		item.Week.Hide()
+		return
+	}
+
+	item.Recent.SetTitle(activityCountString("24H", cfg.ActivityCount(name, 1, now)))
+	item.Week.SetTitle(activityCountString("07D", cfg.ActivityCount(name, 7, now)))
+	item.Recent.Show()
+	item.Week.Show()
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getlantern/systray"
+)
+
+const alertMark = "⚠ "
+
+// alertRules each return the alert messages of a hotspot
+var alertRules = []func(cfg *config, name string) []string{
+	anomalyAlerts,
+	syncAlerts,
+}
+
+type alert struct {
+	Hotspot string // display name
+	Address string
+	Message string
+}
+
+func (a alert) String() string {
+	return fmt.Sprintf("%s: %s", a.Hotspot, a.Message)
+}
+
+type alertsMenuItem struct {
+	MenuItem *systray.MenuItem
+	Rows     []*systray.MenuItem
+}
+
+func newAlertsMenuItem() *alertsMenuItem {
+	item := systray.AddMenuItem("No alerts", "Hotspots that need a look")
+	item.Hide()
+	return &alertsMenuItem{MenuItem: item}
+}
+
+// UpdateAlerts runs the alert rules over every row and logs new alerts
+func (cfg *config) UpdateAlerts() {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	previous := make(map[string]bool)
+	for _, a := range cfg.Alerts {
+		previous[a.String()] = true
+	}
+
+	var alerts []alert
+	for _, order := range cfg.HsSort {
+		for _, rule := range alertRules {
+			for _, msg := range rule(cfg, order.Name) {
+				a := alert{
+					Hotspot: cfg.DisplayName(order.Name),
+					Address: cfg.HsMap[order.Name].Address,
+					Message: msg,
+				}
+				if !previous[a.String()] {
+					fmt.Println("Alert:", a)
+				}
+				alerts = append(alerts, a)
+			}
+		}
+	}
+	cfg.Alerts = alerts
+}
+
+// hasAlert reports whether a hotspot has any alerts
+func (cfg *config) hasAlert(address string) bool {
+	for _, a := range cfg.Alerts {
+		if a.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg *config) updateAlertsMenu() {
+	item := cfg.AlertsMenuItem
+	if item == nil {
+		return
+	}
+	if len(cfg.Alerts) == 0 {
+		item.MenuItem.Hide()
+		return
+	}
+
+	item.MenuItem.SetTitle(fmt.Sprintf("%sAlerts (%d)", alertMark, len(cfg.Alerts)))
+	for len(item.Rows) < len(cfg.Alerts) {
+		item.Rows = append(item.Rows, item.MenuItem.AddSubMenuItem("", ""))
+	}
+	for i, row := range item.Rows {
+		if i < len(cfg.Alerts) {
+			row.SetTitle(cfg.Alerts[i].String())
+			row.Show()
+		} else {
+			row.Hide()
+		}
+	}
+	item.MenuItem.Show()
+}
+
+// flagString joins the anomaly flags of a hotspot for its sub-menu
+func flagString(flags []anomalyFlag) string {
+	var messages []string
+	for _, f := range flags {
+		messages = append(messages, f.Message)
+	}
+	return alertMark + strings.Join(messages, ", ")
+}
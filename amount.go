@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// unitsPerWhole is the fixed-point scale of HNT and USD amounts, matching
+// bones on chain and oracle prices
+const unitsPerWhole = 100000000
+
+// bones is an amount of HNT in its smallest unit, 1e-8 HNT. Amounts are kept
+// exact and only rounded when displayed.
+type bones int64
+
+// usd is an amount of dollars at the same 1e-8 scale as oracle prices
+type usd int64
+
+// HNT returns the amount as a float for display and ratio calculations only
+func (b bones) HNT() float64 {
+	return float64(b) / unitsPerWhole
+}
+
+func (b bones) String() string {
+	return formatFixed(int64(b), 2)
+}
+
+// ToUSD converts the amount at price, the USD value of one HNT
+func (b bones) ToUSD(price usd) usd {
+	// bones * price can overflow int64, so multiply in big.Int
+	product := new(big.Int).Mul(big.NewInt(int64(b)), big.NewInt(int64(price)))
+	return usd(divRound(product, big.NewInt(unitsPerWhole)).Int64())
+}
+
+// MulDiv returns b * num / den rounded half away from zero, without overflow
+func (b bones) MulDiv(num int64, den int64) bones {
+	return bones(mulDiv(int64(b), num, den))
+}
+
+// usdFromFloat converts a dollar amount from the config
+func usdFromFloat(dollars float64) usd {
+	return usd(math.Round(dollars * unitsPerWhole))
+}
+
+// MulDiv returns u * num / den rounded half away from zero, without overflow
+func (u usd) MulDiv(num int64, den int64) usd {
+	return usd(mulDiv(int64(u), num, den))
+}
+
+func (u usd) String() string {
+	return formatFixed(int64(u), 2)
+}
+
+func mulDiv(val int64, num int64, den int64) int64 {
+	product := new(big.Int).Mul(big.NewInt(val), big.NewInt(num))
+	return divRound(product, big.NewInt(den)).Int64()
+}
+
+// formatFixed formats a 1e-8 fixed-point value with the given decimals,
+// rounding half away from zero
+func formatFixed(val int64, decimals int) string {
+	scale := int64(unitsPerWhole)
+	for i := 0; i < decimals; i++ {
+		scale /= 10
+	}
+	rounded := divRound(big.NewInt(val), big.NewInt(scale)).Int64()
+
+	sign := ""
+	if rounded < 0 {
+		sign = "-"
+		rounded = -rounded
+	}
+
+	unit := int64(1)
+	for i := 0; i < decimals; i++ {
+		unit *= 10
+	}
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, rounded)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, rounded/unit, decimals, rounded%unit)
+}
+
+// divRound divides rounding half away from zero
+func divRound(x *big.Int, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	if new(big.Int).Abs(new(big.Int).Mul(r, big.NewInt(2))).Cmp(new(big.Int).Abs(y)) >= 0 {
+		if x.Sign()*y.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
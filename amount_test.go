@@ -0,0 +1,143 @@
+package main
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDivRound(t *testing.T) {
+	tests := []struct {
+		x, y int64
+		want int64
+	}{
+		{0, 5, 0},
+		{6, 3, 2},
+		{4, 3, 1},
+		{5, 3, 2},
+		{-4, 3, -1},
+		{-5, 3, -2},
+		{7, 2, 4},
+		{-7, 2, -4},
+		{7, -2, -4},
+		{-7, -2, 4},
+		{math.MaxInt64, 2, 4611686018427387904},
+		{math.MinInt64, 2, -4611686018427387904},
+	}
+	for _, tt := range tests {
+		got := divRound(big.NewInt(tt.x), big.NewInt(tt.y)).Int64()
+		if got != tt.want {
+			t.Errorf("divRound(%d, %d) = %d, want %d", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFixed(t *testing.T) {
+	tests := []struct {
+		val      int64
+		decimals int
+		want     string
+	}{
+		{0, 2, "0.00"},
+		{150000000, 2, "1.50"},
+		{149999999, 2, "1.50"},
+		{500000, 2, "0.01"},
+		{499999, 2, "0.00"},
+		{-500000, 2, "-0.01"},
+		{-499999, 2, "0.00"},
+		{50000000, 0, "1"},
+		{-50000000, 0, "-1"},
+		{-150000000, 0, "-2"},
+		{123456789, 8, "1.23456789"},
+		{-123456789, 8, "-1.23456789"},
+		{math.MaxInt64, 2, "92233720368.55"},
+		{math.MinInt64, 2, "-92233720368.55"},
+	}
+	for _, tt := range tests {
+		if got := formatFixed(tt.val, tt.decimals); got != tt.want {
+			t.Errorf("formatFixed(%d, %d) = %q, want %q", tt.val, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestToUSD(t *testing.T) {
+	tests := []struct {
+		amount bones
+		price  usd
+		want   usd
+	}{
+		{hnt(1), 1234, 1234},
+		{hnt(-2), 1234, -2468},
+		{1, 50000000, 1},
+		{-1, 50000000, -1},
+		{1, 49999999, 0},
+		// 21M HNT at $100 overflows int64 before dividing
+		{hnt(21000000), usdFromFloat(100), 210000000000000000},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.ToUSD(tt.price); got != tt.want {
+			t.Errorf("%d.ToUSD(%d) = %d, want %d", tt.amount, tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestMulDiv(t *testing.T) {
+	tests := []struct {
+		amount   bones
+		num, den int64
+		want     bones
+	}{
+		{10, 1, 4, 3},
+		{9, 1, 4, 2},
+		{-10, 1, 4, -3},
+		{10, -1, 4, -3},
+		{-9, 1, 4, -2},
+		{math.MaxInt64, 10000, 10000, math.MaxInt64},
+		{math.MaxInt64, 3000, 10000, 2767011611056432742},
+		{math.MaxInt64, 3, 7, 3952873730080618203},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.MulDiv(tt.num, tt.den); got != tt.want {
+			t.Errorf("%d.MulDiv(%d, %d) = %d, want %d", tt.amount, tt.num, tt.den, got, tt.want)
+		}
+	}
+	if got := usd(-10).MulDiv(1, 4); got != -3 {
+		t.Errorf("usd(-10).MulDiv(1, 4) = %d, want -3", got)
+	}
+}
+
+func TestSettlementShares(t *testing.T) {
+	tests := []struct {
+		percent float64
+		gross   bones
+		want    bones
+	}{
+		{30, hnt(10), hnt(3)},
+		{33.33, 123456789, 41148148},
+		{12.5, 4, 1},
+		{12.5, 12, 2},
+		{0.01, 4999, 0},
+		{0.01, 5000, 1},
+		{100, 123456789, 123456789},
+		{0, 123456789, 0},
+		{50, -3, -2},
+		{30, math.MaxInt64, 2767011611056432742},
+	}
+	month := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		split := revenueSplit{Percent: tt.percent, Payee: "host"}
+		if got := split.Share(tt.gross); got != tt.want {
+			t.Errorf("%v%% Share(%d) = %d, want %d", tt.percent, tt.gross, got, tt.want)
+		}
+
+		cfg := testConfig(nil)
+		cfg.HsMap["hs"] = hotspot{Name: "hs", Address: "112abc"}
+		cfg.Hotspots = hotspotSettings{"112abc": {Split: &split}}
+		s := cfg.Settlement(month, map[string]monthRewards{"hs": {Gross: tt.gross}})
+		line := s.Payees[0].Lines[0]
+		if line.PayeeShare+line.Net != line.Gross || s.Payees[0].Total != line.PayeeShare {
+			t.Errorf("%v%% of %d settled as %d + %d, total %d", tt.percent, tt.gross, line.PayeeShare, line.Net, s.Payees[0].Total)
+		}
+	}
+}
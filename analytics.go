@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/getlantern/systray"
+)
+
+const bottomPerformers = 5 // hotspots listed as underperformers
+
+// analyticsPeriods are the windows the fleet is compared over
+var analyticsPeriods = []int{7, 30}
+
+// fleetEntry is a hotspot's rewards over a period
+type fleetEntry struct {
+	Name   string
+	Reward bones
+}
+
+// fleetStats is the distribution of hotspot rewards over a period
+type fleetStats struct {
+	Days    int
+	Total   bones
+	Count   int // hotspots with rewards for the period
+	Median  bones
+	P10     bones
+	P25     bones
+	P75     bones
+	P90     bones
+	Entries []fleetEntry // lowest reward first
+}
+
+// FleetStats summarizes the rewards of every visible hotspot over days
+func (cfg *config) FleetStats(days int) fleetStats {
+	stats := fleetStats{Days: days}
+	var values []float64
+	for _, order := range cfg.HsSort {
+		reward, n := cfg.RewardSum(order.Name, 0, days)
+		if n == 0 {
+			continue
+		}
+		stats.Total += reward
+		stats.Entries = append(stats.Entries, fleetEntry{order.Name, reward})
+		values = append(values, float64(reward))
+	}
+
+	stats.Count = len(values)
+	stats.Median = bones(math.Round(median(values)))
+	stats.P10 = bones(math.Round(percentile(values, 10)))
+	stats.P25 = bones(math.Round(percentile(values, 25)))
+	stats.P75 = bones(math.Round(percentile(values, 75)))
+	stats.P90 = bones(math.Round(percentile(values, 90)))
+	sort.SliceStable(stats.Entries, func(i, j int) bool {
+		return stats.Entries[i].Reward < stats.Entries[j].Reward
+	})
+	return stats
+}
+
+// Bottom returns up to n of the lowest earning hotspots
+func (s fleetStats) Bottom(n int) []fleetEntry {
+	if n > len(s.Entries) {
+		n = len(s.Entries)
+	} else if n < 0 {
+		n = 0
+	}
+	return s.Entries[:n]
+}
+
+// Relative returns reward as a percentage of the fleet median
+func (s fleetStats) Relative(reward bones) (float64, bool) {
+	if s.Median == 0 {
+		return 0, false
+	}
+	return float64(reward) / float64(s.Median) * 100, true
+}
+
+// FleetOnline counts the visible hotspots that are online
+func (cfg *config) FleetOnline() (online, total int) {
+	for _, order := range cfg.HsSort {
+		if cfg.HsMap[order.Name].Status.Online == statusOnline {
+			online++
+		}
+		total++
+	}
+	return online, total
+}
+
+func (cfg *config) statsString(s fleetStats) string {
+	if s.Count == 0 {
+		return fmt.Sprintf("%02dD: n/a", s.Days)
+	}
+	return fmt.Sprintf("%02dD: %s total, median %s (P25 %s, P75 %s)",
+		s.Days, cfg.rewardToString(s.Total), cfg.rewardToString(s.Median),
+		cfg.rewardToString(s.P25), cfg.rewardToString(s.P75))
+}
+
+func onlineString(online, total int) string {
+	if total == 0 {
+		return "Online: n/a"
+	}
+	return fmt.Sprintf("Online: %d of %d (%d%%)", online, total, online*100/total)
+}
+
+func (cfg *config) entryString(s fleetStats, e fleetEntry) string {
+	if rel, ok := s.Relative(e.Reward); ok {
+		return fmt.Sprintf("%s - %s (%.0f%% of median)", cfg.rewardToString(e.Reward), cfg.DisplayName(e.Name), rel)
+	}
+	return fmt.Sprintf("%s - %s", cfg.rewardToString(e.Reward), cfg.DisplayName(e.Name))
+}
+
+// relativeString compares a hotspot with the fleet median of each period
+func (cfg *config) relativeString(name string, stats []fleetStats) string {
+	result := "vs fleet median:"
+	for i, s := range stats {
+		sep := " "
+		if i > 0 {
+			sep = " / "
+		}
+		reward, n := cfg.RewardSum(name, 0, s.Days)
+		if rel, ok := s.Relative(reward); ok && n > 0 {
+			result += fmt.Sprintf("%s%02dD %.0f%%", sep, s.Days, rel)
+		} else {
+			result += fmt.Sprintf("%s%02dD n/a", sep, s.Days)
+		}
+	}
+	return result
+}
+
+type bottomMenuItem struct {
+	MenuItem *systray.MenuItem
+	Rows     []*systray.MenuItem
+}
+
+type analyticsMenuItem struct {
+	MenuItem *systray.MenuItem
+	Periods  []*systray.MenuItem // one row per analytics period
+	Online   *systray.MenuItem
+	Bottom   []*bottomMenuItem // underperformers per analytics period
+}
+
+func newAnalyticsMenuItem() *analyticsMenuItem {
+	item := systray.AddMenuItem("Fleet analytics", "Distribution of rewards across hotspots")
+	analytics := &analyticsMenuItem{MenuItem: item}
+	for _, days := range analyticsPeriods {
+		analytics.Periods = append(analytics.Periods, item.AddSubMenuItem("Loading...", fmt.Sprintf("Rewards over the last %d days", days)))
+	}
+	analytics.Online = item.AddSubMenuItem("Loading...", "Hotspots online")
+	for _, days := range analyticsPeriods {
+		bottom := item.AddSubMenuItem(fmt.Sprintf("Bottom %d (%02dD)", bottomPerformers, days), "Lowest earning hotspots")
+		row := &bottomMenuItem{MenuItem: bottom}
+		for i := 0; i < bottomPerformers; i++ {
+			row.Rows = append(row.Rows, bottom.AddSubMenuItem("", ""))
+		}
+		analytics.Bottom = append(analytics.Bottom, row)
+	}
+	return analytics
+}
+
+func (cfg *config) fleetStatsByPeriod() []fleetStats {
+	stats := make([]fleetStats, len(analyticsPeriods))
+	for i, days := range analyticsPeriods {
+		stats[i] = cfg.FleetStats(days)
+	}
+	return stats
+}
+
+func (cfg *config) updateAnalytics(stats []fleetStats) {
+	item := cfg.AnalyticsMenuItem
+	if item == nil {
+		return
+	}
+
+	for i, s := range stats {
+		item.Periods[i].SetTitle(cfg.statsString(s))
+
+		bottom := s.Bottom(bottomPerformers)
+		for j, row := range item.Bottom[i].Rows {
+			if j < len(bottom) {
+				row.SetTitle(cfg.entryString(s, bottom[j]))
+				row.Show()
+			} else {
+				row.Hide()
+			}
+		}
+	}
+	item.Online.SetTitle(onlineString(cfg.FleetOnline()))
+}
+
+// WriteAnalytics writes the fleet distribution, the bottom n hotspots and
+// where each hotspot stands against the fleet median
+func (cfg *config) WriteAnalytics(w io.Writer, n int) {
+	stats := cfg.fleetStatsByPeriod()
+	fmt.Fprintln(w, onlineString(cfg.FleetOnline()))
+	for _, s := range stats {
+		fmt.Fprintf(w, "\n%s\n", cfg.statsString(s))
+		if s.Count == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  P10 %s, P90 %s\n", cfg.rewardToString(s.P10), cfg.rewardToString(s.P90))
+		fmt.Fprintf(w, "  Bottom %d:\n", n)
+		for _, e := range s.Bottom(n) {
+			fmt.Fprintf(w, "    %s\n", cfg.entryString(s, e))
+		}
+	}
+
+	fmt.Fprintln(w)
+	for _, order := range cfg.HsSort {
+		fmt.Fprintf(w, "%s: %s\n", cfg.DisplayName(order.Name), cfg.relativeString(order.Name, stats))
+	}
+}
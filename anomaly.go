@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Anomaly scoring methods
+const (
+	anomalyMAD    = "mad"    // robust z-score from the median absolute deviation
+	anomalyZScore = "zscore" // z-score from the mean and standard deviation
+)
+
+const (
+	recentDays       = 7 // days scored as a block to catch slow slides
+	defaultThreshold = 3.5
+	defaultZeroDays  = 2
+)
+
+type anomalySettings struct {
+	Method    string  `json:"method,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"` // score beyond which a day is an outlier
+	Lookback  int     `json:"lookback,omitempty"`  // baseline days before the recent week
+	ZeroDays  int     `json:"zero_days,omitempty"` // consecutive days without rewards to flag
+}
+
+func (s anomalySettings) validate() error {
+	switch s.Method {
+	case "", anomalyMAD, anomalyZScore:
+	default:
+		return errors.New("Invalid anomaly method")
+	}
+	if s.Threshold < 0 || s.Lookback < 0 || s.ZeroDays < 0 {
+		return errors.New("Invalid anomaly settings")
+	}
+	return nil
+}
+
+func (s anomalySettings) threshold() float64 {
+	if s.Threshold == 0 {
+		return defaultThreshold
+	}
+	return s.Threshold
+}
+
+func (s anomalySettings) lookback() int {
+	if s.Lookback == 0 {
+		return defaultLookback
+	}
+	return s.Lookback
+}
+
+func (s anomalySettings) zeroDays() int {
+	if s.ZeroDays == 0 {
+		return defaultZeroDays
+	}
+	return s.ZeroDays
+}
+
+// anomaly scores the latest completed day and the recent week of a hotspot
+// against its own baseline
+type anomaly struct {
+	Score    float64 // of the previous day
+	Slide    float64 // of the mean of the recent week
+	ZeroDays int     // consecutive completed days without rewards
+	Scored   bool    // whether there was enough history to score
+}
+
+// Low reports whether the previous day is an outlier on the low side
+func (a anomaly) Low(threshold float64) bool {
+	return a.Scored && a.Score < -threshold
+}
+
+// High reports whether the previous day is an outlier on the high side
+func (a anomaly) High(threshold float64) bool {
+	return a.Scored && a.Score > threshold
+}
+
+// Sliding reports whether the recent week is well below the baseline
+func (a anomaly) Sliding(threshold float64) bool {
+	return a.Scored && a.Slide < -threshold
+}
+
+// Anomaly scores a hotspot. The baseline is the lookback days before the
+// recent week, so a slide doesn't drag its own baseline down.
+func (cfg *config) Anomaly(name string) anomaly {
+	rewards := cfg.HsRewards[name]
+	var a anomaly
+	for i := 1; i < len(rewards) && rewards[i].Sum == 0; i++ {
+		a.ZeroDays++
+	}
+
+	lookback := cfg.AnomalySettings.lookback()
+	if len(rewards) < 1+recentDays+lookback/2 {
+		return a
+	}
+
+	var recent, baseline []float64
+	for i := 1; i < len(rewards) && i <= recentDays+lookback; i++ {
+		if i <= recentDays {
+			recent = append(recent, float64(rewards[i].Sum))
+		} else {
+			baseline = append(baseline, float64(rewards[i].Sum))
+		}
+	}
+
+	center, spread := baselineStats(cfg.AnomalySettings.Method, baseline)
+	if spread == 0 {
+		return a
+	}
+
+	a.Score = (recent[0] - center) / spread
+	a.Slide = (mean(recent) - center) / (spread / math.Sqrt(float64(len(recent))))
+	a.Scored = true
+	return a
+}
+
+// anomalyFlag describes something unusual about a hotspot
+type anomalyFlag struct {
+	Message string
+	Alert   bool // whether it's a problem worth an alert
+}
+
+// Flags describes what's unusual about a hotspot, empty when nothing is
+func (cfg *config) Flags(name string) []anomalyFlag {
+	a := cfg.Anomaly(name)
+	threshold := cfg.AnomalySettings.threshold()
+
+	var flags []anomalyFlag
+	if a.ZeroDays >= cfg.AnomalySettings.zeroDays() {
+		flags = append(flags, anomalyFlag{fmt.Sprintf("no rewards for %d days", a.ZeroDays), true})
+	} else if a.Low(threshold) {
+		flags = append(flags, anomalyFlag{fmt.Sprintf("unusually low yesterday (score %.1f)", a.Score), true})
+	}
+	if a.High(threshold) {
+		flags = append(flags, anomalyFlag{fmt.Sprintf("unusually high yesterday (score %.1f)", a.Score), false})
+	}
+	if a.Sliding(threshold) {
+		flags = append(flags, anomalyFlag{fmt.Sprintf("sliding over the last %d days (score %.1f)", recentDays, a.Slide), true})
+	}
+	return flags
+}
+
+func anomalyAlerts(cfg *config, name string) []string {
+	var alerts []string
+	for _, f := range cfg.Flags(name) {
+		if f.Alert {
+			alerts = append(alerts, f.Message)
+		}
+	}
+	return alerts
+}
+
+// baselineStats returns the center and spread of the baseline as the
+// median and scaled MAD, or the mean and standard deviation
+func baselineStats(method string, baseline []float64) (center float64, spread float64) {
+	if method == anomalyZScore {
+		center = mean(baseline)
+		var sq float64
+		for _, v := range baseline {
+			sq += (v - center) * (v - center)
+		}
+		if len(baseline) > 1 {
+			spread = math.Sqrt(sq / float64(len(baseline)-1))
+		}
+		return center, spread
+	}
+
+	center = median(baseline)
+	deviations := make([]float64, len(baseline))
+	for i, v := range baseline {
+		deviations[i] = math.Abs(v - center)
+	}
+	// 1.4826 scales the MAD to the standard deviation of normal data
+	spread = 1.4826 * median(deviations)
+	if spread == 0 {
+		// More than half the days are equal, fall back to the mean deviation
+		spread = 1.2533 * mean(deviations)
+	}
+	return center, spread
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	return percentile(values, 50)
+}
+
+// percentile interpolates between the closest ranks
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+const (
+	breakdownDays     = 14  // the last 7 days and the 7 before them
+	breakdownMinutes  = 60  // minimum time between breakdown fetches
+	maxRewardPages    = 200 // stop paging reward entries after this many pages
+	otherRewardType   = "other"
+	breakdownTopMoves = 2 // reward types listed as having moved
+)
+
+// rewardTypes are the reward types reported by the API in display order.
+// Anything else is counted as otherRewardType.
+var rewardTypes = []struct {
+	Type  string
+	Title string
+}{
+	{"poc_challengers", "Challenger"},
+	{"poc_challengees", "Beacon"},
+	{"poc_witnesses", "Witness"},
+	{"data_credits", "Data"},
+	{"consensus", "Consensus"},
+	{otherRewardType, "Other"},
+}
+
+// rewardBreakdown sums rewards by reward type
+type rewardBreakdown map[string]bones
+
+func rewardTypeKey(t string) string {
+	for _, rt := range rewardTypes {
+		if rt.Type == t {
+			return t
+		}
+	}
+	return otherRewardType
+}
+
+func rewardTypeTitle(t string) string {
+	for _, rt := range rewardTypes {
+		if rt.Type == t {
+			return rt.Title
+		}
+	}
+	return t
+}
+
+// fetchRewardEntries pages through the individual rewards of a hotspot,
+// using get for the requests
+func fetchRewardEntries(ctx context.Context, get func(context.Context, string, interface{}) error, address string, minTime time.Time, maxTime time.Time) ([]rewardEntry, error) {
+	var entries []rewardEntry
+	cursor := ""
+	for page := 0; page < maxRewardPages; page++ {
+		resp, err := getHotspotRewardEntries(ctx, get, address, minTime, maxTime, cursor)
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, resp.Data...)
+
+		if resp.Cursor == "" {
+			break
+		}
+		cursor = resp.Cursor
+	}
+	return entries, nil
+}
+
+// breakdownOf sums entries by type from up to but not including to
+func breakdownOf(entries []rewardEntry, from time.Time, to time.Time) rewardBreakdown {
+	result := make(rewardBreakdown)
+	for _, e := range entries {
+		if e.Timestamp.Before(from) || !e.Timestamp.Before(to) {
+			continue
+		}
+		result[rewardTypeKey(e.Type)] += e.Amount
+	}
+	return result
+}
+
+// GetRewardBreakdowns fetches the individual rewards of every visible
+// hotspot, at most once every breakdownMinutes
+func (cfg *config) GetRewardBreakdowns(ctx context.Context) error {
+	now := cfg.Clock.Now()
+	if now.Sub(cfg.BreakdownFetched) < breakdownMinutes*time.Minute {
+		return nil
+	}
+
+	entries, err := cfg.fetchAllRewardEntries(ctx, cfg.Clock.WindowStart(now, breakdownDays), now)
+	if err != nil {
+		return err
+	}
+
+	cfg.mu.Lock()
+	cfg.HsBreakdown = entries
+	cfg.BreakdownFetched = now
+	cfg.mu.Unlock()
+	return nil
+}
+
+// fetchAllRewardEntries fetches the individual rewards of every visible
+// hotspot between from and to, by hotspot name
+func (cfg *config) fetchAllRewardEntries(ctx context.Context, from time.Time, to time.Time) (map[string][]rewardEntry, error) {
+	// Copied under the lock as reloads replace HsMap
+	addresses := make(map[string]string)
+	cfg.mu.Lock()
+	for name, hs := range cfg.HsMap {
+		if !cfg.Hotspots[hs.Address].Hidden {
+			addresses[name] = hs.Address
+		}
+	}
+	cfg.mu.Unlock()
+
+	result := make(map[string][]rewardEntry)
+	for name, address := range addresses {
+		// The range ends now, so it's never cached
+		entries, err := fetchRewardEntries(ctx, requestGetUncached, address, from, to)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			handleSoftError(err, "Failed to get reward breakdown")
+			continue
+		}
+		result[name] = entries
+		if err := cfg.sleep(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// typeChange is how much one reward type moved between two windows
+type typeChange struct {
+	Type     string
+	Current  bones
+	Previous bones
+}
+
+func (c typeChange) Diff() bones {
+	return c.Current - c.Previous
+}
+
+// BreakdownChanges compares the reward types of the last days with the days
+// before them, largest move first
+func (cfg *config) BreakdownChanges(name string, days int, now time.Time) ([]typeChange, bool) {
+	entries, found := cfg.HsBreakdown[name]
+	if !found {
+		return nil, false
+	}
+
+	start := cfg.Clock.WindowStart(now, days)
+	prevStart := start.AddDate(0, 0, -days)
+	if cfg.Clock.Rolling {
+		prevStart = start.Add(-time.Duration(days) * day)
+	}
+	current := breakdownOf(entries, start, now)
+	previous := breakdownOf(entries, prevStart, start)
+
+	var changes []typeChange
+	for _, rt := range rewardTypes {
+		c := typeChange{Type: rt.Type, Current: current[rt.Type], Previous: previous[rt.Type]}
+		if c.Current != 0 || c.Previous != 0 {
+			changes = append(changes, c)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return abs(changes[i].Diff()) > abs(changes[j].Diff())
+	})
+	return changes, true
+}
+
+func abs(b bones) bones {
+	if b < 0 {
+		return -b
+	}
+	return b
+}
+
+// movesString names the reward types that moved the most
+func (cfg *config) movesString(label string, changes []typeChange) string {
+	var moves []string
+	for i, c := range changes {
+		if i == breakdownTopMoves || c.Diff() == 0 {
+			break
+		}
+		sign := ""
+		if c.Diff() > 0 {
+			sign = "+"
+		}
+		moves = append(moves, fmt.Sprintf("%s %s%s", rewardTypeTitle(c.Type), sign, cfg.rewardToString(c.Diff())))
+	}
+	if len(moves) == 0 {
+		return fmt.Sprintf("%s moved by type: none", label)
+	}
+	return fmt.Sprintf("%s moved by type: %s", label, strings.Join(moves, ", "))
+}
+
+type breakdownMenuItem struct {
+	MenuItem *systray.MenuItem
+	Rows     []*systray.MenuItem // one row per reward type
+}
+
+func newBreakdownMenuItem(parent *systray.MenuItem) *breakdownMenuItem {
+	item := parent.AddSubMenuItem("Rewards by type", "Rewards by the kind of work they were paid for")
+	breakdown := &breakdownMenuItem{MenuItem: item}
+	for _, rt := range rewardTypes {
+		breakdown.Rows = append(breakdown.Rows, item.AddSubMenuItem("Loading...", rt.Title+" rewards"))
+	}
+	return breakdown
+}
+
+func (cfg *config) updateBreakdown(item *breakdownMenuItem, name string, now time.Time) {
+	day, _ := cfg.BreakdownChanges(name, 1, now)
+	week, found := cfg.BreakdownChanges(name, 7, now)
+	if !found {
+		item.MenuItem.Hide()
+		return
+	}
+
+	for i, rt := range rewardTypes {
+		d := typeDiff(day, rt.Type, 1)
+		w := typeDiff(week, rt.Type, 7)
+		if d.Current == 0 && d.Previous == 0 && w.Current == 0 && w.Previous == 0 {
+			item.Rows[i].Hide()
+			continue
+		}
+		item.Rows[i].SetTitle(fmt.Sprintf("%s: %s, %s", rt.Title,
+			cfg.grossDiffString("24H", d), cfg.grossDiffString("07D", w)))
+		item.Rows[i].Show()
+	}
+	item.MenuItem.Show()
+}
+
+// typeDiff returns the change of a reward type as a rewardDiff so it's shown
+// like the other reward rows
+func typeDiff(changes []typeChange, rewardType string, days int) rewardDiff {
+	d := rewardDiff{Days: days, CurrentDays: days, PreviousDays: days}
+	for _, c := range changes {
+		if c.Type == rewardType {
+			d.Current, d.Previous = c.Current, c.Previous
+		}
+	}
+	return d
+}
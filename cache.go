@@ -0,0 +1,159 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const cacheMaxAge = 3 * 24 * time.Hour // unused entries are pruned after this
+
+// responseCache keeps API responses on disk between refreshes and restarts
+var responseCache = newHTTPCache()
+
+// httpCache is a disk cache for API responses keyed by request URL. A nil
+// cache is valid and never has any entries.
+type httpCache struct {
+	dir string
+}
+
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"last_modified"`
+	Expires      time.Time `json:"expires"`
+	Body         []byte    `json:"body"`
+}
+
+func newHTTPCache() *httpCache {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	return &httpCache{dir: filepath.Join(cacheDir, "helium-systray", "http")}
+}
+
+// Fresh reports whether the entry can be used without asking the API
+func (e cacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+func (c *httpCache) Load(url string) (cacheEntry, bool) {
+	var entry cacheEntry
+	if c == nil {
+		return entry, false
+	}
+
+	raw, err := ioutil.ReadFile(c.path(url))
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(raw, &entry); err != nil || entry.URL != url {
+		return entry, false
+	}
+
+	return entry, true
+}
+
+func (c *httpCache) Store(entry cacheEntry) error {
+	if c == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so readers never see a partial entry
+	tmp, err := ioutil.TempFile(c.dir, "entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(entry.URL))
+}
+
+// Prune removes entries that haven't been written for longer than maxAge
+func (c *httpCache) Prune(maxAge time.Duration) {
+	if c == nil {
+		return
+	}
+
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	for _, f := range files {
+		if f.ModTime().Before(cutoff) {
+			os.Remove(filepath.Join(c.dir, f.Name()))
+		}
+	}
+}
+
+func (c *httpCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cacheExpiry works out until when a response may be used without
+// revalidation, and whether it's worth caching at all. Responses that are
+// neither fresh for a while nor revalidatable are never reused, so they
+// aren't stored.
+func cacheExpiry(header http.Header, now time.Time) (expires time.Time, storable bool) {
+	expires, storable = responseExpiry(header, now)
+	revalidatable := header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+	return expires, storable && (revalidatable || expires.After(now))
+}
+
+func responseExpiry(header http.Header, now time.Time) (time.Time, bool) {
+	directives := strings.Split(header.Get("Cache-Control"), ",")
+	maxAge := -1
+	for _, d := range directives {
+		d = strings.ToLower(strings.TrimSpace(d))
+		switch {
+		case d == "no-store":
+			return now, false
+		case d == "no-cache":
+			return now, true
+		case strings.HasPrefix(d, "max-age="):
+			if secs, err := strconv.Atoi(strings.TrimPrefix(d, "max-age=")); err == nil {
+				maxAge = secs
+			}
+		}
+	}
+
+	if maxAge >= 0 {
+		age, _ := strconv.Atoi(header.Get("Age"))
+		return now.Add(time.Duration(maxAge-age) * time.Second), true
+	}
+
+	if exp, err := http.ParseTime(header.Get("Expires")); err == nil {
+		return exp, true
+	}
+
+	// No freshness info, revalidate with ETag/Last-Modified next time
+	return now, true
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+)
+
+const cliUsage = `Usage: helium-systray [command] [flags]
+
+Without a command the menu bar app is started.
+
+Commands:
+  summary      print rewards, profit and payback of every hotspot
+  settlement   print the monthly revenue split settlement per payee
+  analytics    print the fleet distribution and the lowest earning hotspots
+
+Commands for the running app:
+  refresh        refresh hotspot data now
+  reload-config  reload the JSON config
+  status         print the total, hotspots and alerts shown in the menu
+`
+
+// runCommand runs a CLI command instead of the tray app and returns the
+// exit code
+func runCommand(args []string) int {
+	headless = true
+	appClipboard = writerClipboard{w: os.Stdout}
+
+	switch args[0] {
+	case "summary":
+		return runSummary(args[1:])
+	case "settlement":
+		return runSettlement(args[1:])
+	case "analytics":
+		return runAnalytics(args[1:])
+	case ipcRefresh, ipcReload:
+		return runRemote(args[0], args[1:])
+	case ipcStatus:
+		return runStatus(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(cliUsage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", args[0], cliUsage)
+		return 2
+	}
+}
+
+// loadHeadlessConfig loads the config file without any of the menu
+func loadHeadlessConfig() (*config, error) {
+	as, err := loadAppSettings(appSettingsPath)
+	if err != nil {
+		return nil, err
+	}
+	return newConfig(as), nil
+}
+
+// FetchHeadless fetches hotspots, their rewards and prices
+func (cfg *config) FetchHeadless(ctx context.Context) error {
+	if err := cfg.FetchAllHotspots(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetHNTPrice(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetBlockHeight(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetPriceHistory(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetHotspotRewards(ctx); err != nil {
+		return err
+	}
+
+	cfg.SortHotspots()
+	return nil
+}
+
+// WriteSummary writes the rewards of every hotspot in sort order, along with
+// their profit and payback when costs are set
+func (cfg *config) WriteSummary(w io.Writer) {
+	now := cfg.Clock.Now()
+	fmt.Fprintf(w, "Total today: %s\n", cfg.rewardToString(cfg.Total))
+	fmt.Fprintln(w, cfg.outlookString(cfg.FleetOutlook(now)))
+
+	for _, order := range cfg.HsSort {
+		fmt.Fprintf(w, "\n%s (%s)\n", cfg.DisplayName(order.Name), cfg.HotspotStatus(order.Name))
+		for _, win := range cfg.RewardWindows {
+			fmt.Fprintf(w, "  %s\n", cfg.rewardDiffString(win.Label(), cfg.RewardDiff(order.Name, win.Length(now))))
+		}
+		fmt.Fprintf(w, "  %s\n", cfg.outlookString(cfg.HotspotOutlook(order.Name, now)))
+		if cfg.Nearby != "" {
+			fmt.Fprintf(w, "  %s\n", cfg.nearbyString(order.Name))
+		}
+		if _, found := cfg.HsActivity[order.Name]; found {
+			fmt.Fprintf(w, "  %s\n", cfg.activityTitle(order.Name, now))
+			fmt.Fprintf(w, "    %s\n", activityCountString("24H", cfg.ActivityCount(order.Name, 1, now)))
+			fmt.Fprintf(w, "    %s\n", activityCountString("07D", cfg.ActivityCount(order.Name, 7, now)))
+		}
+		if day, found := cfg.BreakdownChanges(order.Name, 1, now); found {
+			week, _ := cfg.BreakdownChanges(order.Name, 7, now)
+			fmt.Fprintf(w, "  %s\n  %s\n", cfg.movesString("24H", day), cfg.movesString("07D", week))
+		}
+		for _, f := range cfg.Flags(order.Name) {
+			fmt.Fprintf(w, "  %s%s\n", alertMark, f.Message)
+		}
+
+		if r, found := cfg.ROI(order.Name, now); found {
+			earned, costs, payback := r.Lines(now)
+			fmt.Fprintf(w, "  %s\n  %s\n  Net profit: %s USD\n  %s\n", earned, costs, r.Profit, payback)
+		}
+	}
+}
+
+func runSummary(args []string) int {
+	flags := flag.NewFlagSet("summary", flag.ContinueOnError)
+	dollars := flags.Bool("usd", false, "show rewards in USD")
+	markdown := flags.Bool("markdown", false, "print the fleet summary as a Markdown table")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	cfg, err := loadHeadlessConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	cfg.ConvertToDollars = *dollars
+
+	if err := cfg.FetchHeadless(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// The table goes through the same copy action as the menu, which
+	// prints to stdout when headless
+	if *markdown {
+		if err := cfg.CopyFleetSummary(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	if err := cfg.GetHotspotActivity(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := cfg.GetRewardBreakdowns(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := cfg.GetNearby(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg.WriteSummary(os.Stdout)
+	return 0
+}
+
+func runAnalytics(args []string) int {
+	flags := flag.NewFlagSet("analytics", flag.ContinueOnError)
+	dollars := flags.Bool("usd", false, "show rewards in USD")
+	bottom := flags.Int("n", bottomPerformers, "number of lowest earning hotspots to list")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	cfg, err := loadHeadlessConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	cfg.ConvertToDollars = *dollars
+
+	if err := cfg.FetchHeadless(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg.WriteAnalytics(os.Stdout, *bottom)
+	return 0
+}
+
+// runRemote sends a command without output to the running app
+func runRemote(command string, args []string) int {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if _, err := sendCommand(command); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func runStatus(args []string) int {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the status as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	resp, err := sendCommand(ipcStatus)
+	if err == nil && resp.Status == nil {
+		err = errors.New("No status in response")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	status := resp.Status
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(status); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Printf("Total today: %s HNT\n", status.Total)
+	for _, hs := range status.Hotspots {
+		fmt.Printf("%s (%s): %s HNT\n", hs.Name, hs.Status, hs.Today)
+	}
+	for _, a := range status.Alerts {
+		fmt.Printf("%s%s\n", alertMark, a)
+	}
+	return 0
+}
+
+// commandContext is cancelled on interrupt so in-flight requests stop
+func commandContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(interrupt)
+	}()
+	return ctx, cancel
+}
+
+func runSettlement(args []string) int {
+	flags := flag.NewFlagSet("settlement", flag.ContinueOnError)
+	month := flags.String("month", "", "month to settle as YYYY-MM, defaults to the previous month")
+	format := flags.String("format", "csv", "output format, csv or json")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q\n", *format)
+		return 2
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	cfg, err := loadHeadlessConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	settleMonth := previousMonth(cfg.Clock.Now())
+	if *month != "" {
+		settleMonth, err = time.ParseInLocation("2006-01", *month, cfg.Clock.Location)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid month %q\n", *month)
+			return 2
+		}
+	}
+
+	if err := cfg.FetchAllHotspots(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	rewards, err := cfg.FetchMonthRewards(ctx, settleMonth)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	s := cfg.Settlement(settleMonth, rewards)
+	if *format == "json" {
+		err = s.WriteJSON(os.Stdout)
+	} else {
+		err = s.WriteCSV(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboard receives text copied from the menu
+type clipboard interface {
+	Copy(text string) error
+}
+
+// appClipboard is the clipboard menu actions copy to
+var appClipboard clipboard = commandClipboard{}
+
+// commandClipboard is the system clipboard, using the platform's clipboard
+// command
+type commandClipboard struct{}
+
+func (commandClipboard) Copy(text string) error {
+	name, args, err := clipboardCommand()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// writerClipboard writes copied text to a writer, for running without a
+// desktop session
+type writerClipboard struct {
+	w io.Writer
+}
+
+func (c writerClipboard) Copy(text string) error {
+	_, err := fmt.Fprintln(c.w, text)
+	return err
+}
+
+// copyToClipboard copies text to appClipboard
+func copyToClipboard(text string) error {
+	return appClipboard.Copy(text)
+}
+
+func clipboardCommand() (string, []string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return "pbcopy", nil, nil
+	case "windows":
+		return "clip", nil, nil
+	}
+
+	// Other platforms need one of the X11 or Wayland clipboard tools
+	candidates := []struct {
+		name string
+		args []string
+	}{
+		{"xclip", []string{"-selection", "clipboard"}},
+		{"xsel", []string{"--clipboard", "--input"}},
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append([]struct {
+			name string
+			args []string
+		}{{"wl-copy", nil}}, candidates...)
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c.name); err == nil {
+			return c.name, c.args, nil
+		}
+	}
+	return "", nil, errors.New("No clipboard command found, install xclip, xsel or wl-copy")
+}
@@ -1,9 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"math"
-	"sort"
+	"sync"
 	"time"
 
 	"github.com/getlantern/systray"
@@ -12,54 +13,306 @@ import (
 
 type sortOrder struct {
 	Name   string
-	Reward float64
+	Reward bones
+}
+
+// rewardDiff is the reward of a window compared to the period before it
+type rewardDiff struct {
+	Current      bones
+	Previous     bones
+	Days         int   // length of each period
+	CurrentDays  int   // days with data in the current period
+	PreviousDays int   // days with data in the previous period
+	Net          bones // our share of Current after revenue splits
+	Split        bool  // whether any revenue split applies
+}
+
+// Comparable reports whether both periods have data for every day
+func (d rewardDiff) Comparable() bool {
+	return d.CurrentDays == d.Days && d.PreviousDays == d.Days
+}
+
+// Diff is the change from the previous period, or 0 if they can't be compared
+func (d rewardDiff) Diff() bones {
+	if !d.Comparable() {
+		return 0
+	}
+	return d.Current - d.Previous
+}
+
+// Add sums two diffs. A hotspot without data is left out, and a hotspot
+// with less history makes the sum partial.
+func (d rewardDiff) Add(o rewardDiff) rewardDiff {
+	switch {
+	case o.CurrentDays == 0:
+		return d
+	case d.CurrentDays == 0:
+		return o
+	}
+
+	d.Current += o.Current
+	d.Previous += o.Previous
+	d.Net += o.Net
+	d.Split = d.Split || o.Split
+	if o.CurrentDays < d.CurrentDays {
+		d.CurrentDays = o.CurrentDays
+	}
+	if o.PreviousDays < d.PreviousDays {
+		d.PreviousDays = o.PreviousDays
+	}
+	return d
 }
 
 type config struct {
-	AccountAddresses   []string            // hotspot account addresses
-	HotspotAddresses   []string            // individual hotspot addresses
-	Total              float64             // total rewards to be displayed in the menu
-	SkipHotspotRefresh bool                // option to skip refresh for initial load
-	ConvertToDollars   bool                // convert HNT to dollars
-	Price              int                 // dollar conversion value
-	HsMap              map[string]hotspot  // map of hotspots
-	HsRewards          map[string][]reward // 60 day reward data of hotspots
-	HsMenuItems        []hotspotMenuItem   // slice of view rows
-	HsSort             []sortOrder         // sorting order
-}
-
-func (cfg *config) FetchAllHotspots() {
+	AccountAddresses   []string                   // hotspot account addresses
+	HotspotAddresses   []string                   // individual hotspot addresses
+	Total              bones                      // total rewards to be displayed in the menu
+	SkipHotspotRefresh bool                       // option to skip refresh for initial load
+	Height             int                        // current block height of the chain
+	SyncBlocks         int                        // blocks behind before a hotspot is syncing
+	ConvertToDollars   bool                       // convert HNT to dollars
+	Price              usd                        // dollar conversion value
+	DailyPrices        map[string]usd             // average price by day, for hotspots with costs
+	HsMap              map[string]hotspot         // map of hotspots
+	RewardDays         int                        // days of reward history to keep
+	RewardWindows      []rewardWindow             // reward comparison windows shown per hotspot
+	Clock              dayClock                   // timezone and mode of reward days
+	Hotspots           hotspotSettings            // per hotspot settings by address
+	SortBy             string                     // sort mode of hotspot rows
+	Settings           appSettings                // settings as loaded from the config file
+	HsRewards          map[string][]reward        // daily reward data of hotspots, newest first
+	Groups             []hotspotGroup             // named groups of hotspots
+	TitleGroup         string                     // group shown in the title, all hotspots if empty
+	HsMenuItems        []*hotspotMenuItem         // slice of view rows outside of groups
+	GroupMenuItems     []*groupMenuItem           // group view rows, in the order of Groups
+	Accounts           map[string]account         // tracked accounts by address
+	AccountMenuItems   []*accountMenuItem         // account view rows, in the order of AccountAddresses
+	Forecast           forecastSettings           // forecast model
+	AnomalySettings    anomalySettings            // anomaly scoring of daily rewards
+	Alerts             []alert                    // alerts from the last refresh
+	AlertsMenuItem     *alertsMenuItem            // list of alerts
+	AnalyticsMenuItem  *analyticsMenuItem         // fleet distribution and underperformers
+	HsActivity         map[string]hotspotActivity // recent witnesses and beacons
+	ActivityFetched    time.Time                  // when HsActivity was last fetched
+	HsBreakdown        map[string][]rewardEntry   // individual rewards of the last breakdownDays
+	BreakdownFetched   time.Time                  // when HsBreakdown was last fetched
+	Nearby             string                     // area nearby hotspots are compared in
+	Explorer           explorerSettings           // explorer link templates
+	NearbyAreas        map[string]nearbyArea      // nearby rewards by hex or city
+	NearbyFetched      time.Time                  // when NearbyAreas was last fetched
+	ForecastMenuItem   *systray.MenuItem          // forecast of all rows
+	MoreMenuItem       *systray.MenuItem          // top level rows added after the menu was built
+	HsSort             []sortOrder                // sorting order
+
+	mu sync.Mutex // guards view data shared with the click handling routines
+}
+
+func (cfg *config) FetchAllHotspots(ctx context.Context) error {
+	hsMap, err := fetchHotspots(ctx, cfg.AccountAddresses, cfg.HotspotAddresses)
+	if err != nil {
+		return err
+	}
+
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	cfg.setHotspots(hsMap)
+	return nil
+}
+
+// fetchHotspots gets the hotspots of accounts and the individual hotspots,
+// by name
+func fetchHotspots(ctx context.Context, accounts []string, hotspots []string) (map[string]hotspot, error) {
+	hsMap := make(map[string]hotspot)
+
 	// Get hotspots from accounts
-	for _, addr := range cfg.AccountAddresses {
-		hotspotsResp, err := getAccountHotspots(addr)
+	for _, addr := range accounts {
+		hotspotsResp, err := getAccountHotspots(ctx, addr)
 		if err != nil {
-			handleError(err, "Failed to fetch hotspots")
+			return nil, err
 		}
 
-		// Populate hotspot data and menu items
 		for _, hs := range hotspotsResp.Data {
-			cfg.HsMap[hs.Name] = hs
-			cfg.HsMenuItems = append(cfg.HsMenuItems, newHotspotMenuItem(hs.Name))
+			hsMap[hs.Name] = hs
 		}
 	}
 
 	// Get individual hotspots by address
-	for _, addr := range cfg.HotspotAddresses {
-		hotspotResp, err := getHotspot(addr)
+	for _, addr := range hotspots {
+		hotspotResp, err := getHotspot(ctx, addr)
 		if err != nil {
-			handleError(err, "Failed to fetch hotspot")
+			return nil, err
 		}
 
-		// Populate hotspot data and menu items
 		hs := hotspotResp.Data
-		cfg.HsMap[hs.Name] = hs
-		cfg.HsMenuItems = append(cfg.HsMenuItems, newHotspotMenuItem(hs.Name))
+		hsMap[hs.Name] = hs
+	}
+	return hsMap, nil
+}
+
+// setHotspots swaps in hsMap and makes sure there is a menu item for each
+// hotspot. Callers hold cfg.mu.
+func (cfg *config) setHotspots(hsMap map[string]hotspot) {
+	cfg.HsMap = hsMap
+	cfg.pruneSort()
+	if !headless {
+		cfg.addMenuItems()
+	}
+}
+
+// addMenuItems adds group and hotspot rows until there is one for every
+// group and visible hotspot. Rows are reused across refreshes and reloads.
+// Menu items can't be inserted once the rest of the menu is built, so top
+// level rows a reload needs on top of the first ones go in the "More..."
+// sub-menu reserved at the end of the section.
+func (cfg *config) addMenuItems() {
+	parent := cfg.MoreMenuItem
+	added := len(cfg.GroupMenuItems) + len(cfg.HsMenuItems) + len(cfg.AccountMenuItems)
+
+	groupCounts := make([]int, len(cfg.Groups))
+	ungrouped := 0
+	for _, hs := range cfg.HsMap {
+		if cfg.Hotspots[hs.Address].Hidden {
+			continue
+		}
+		if g := cfg.GroupIndex(hs.Address); g >= 0 {
+			groupCounts[g]++
+		} else {
+			ungrouped++
+		}
+	}
+
+	for len(cfg.GroupMenuItems) < len(cfg.Groups) {
+		cfg.GroupMenuItems = append(cfg.GroupMenuItems, newGroupMenuItem(parent, cfg.RewardWindows))
+	}
+	for g, count := range groupCounts {
+		item := cfg.GroupMenuItems[g]
+		for len(item.Rows) < count {
+			item.Rows = append(item.Rows, newHotspotMenuItem(item.MenuItem, cfg.RewardWindows, cfg.Explorer.hotspotLinks()))
+		}
+	}
+	for len(cfg.HsMenuItems) < ungrouped {
+		cfg.HsMenuItems = append(cfg.HsMenuItems, newHotspotMenuItem(parent, cfg.RewardWindows, cfg.Explorer.hotspotLinks()))
+	}
+	cfg.addAccountMenuItems(parent)
+
+	if cfg.MoreMenuItem == nil {
+		cfg.MoreMenuItem = systray.AddMenuItem("More...", "Hotspots, groups and accounts added since the app started")
+		cfg.MoreMenuItem.Hide()
+	} else if len(cfg.GroupMenuItems)+len(cfg.HsMenuItems)+len(cfg.AccountMenuItems) > added {
+		cfg.MoreMenuItem.Show()
 	}
 }
 
-func (cfg *config) GetHNTPrice() {
+// pruneSort drops hotspots that are no longer tracked or are now hidden
+// from the sort order, so the view only shows hotspots that have a row until
+// the next refresh rebuilds the order
+func (cfg *config) pruneSort() {
+	var hsSort []sortOrder
+	var total bones
+	for _, order := range cfg.HsSort {
+		hs, found := cfg.HsMap[order.Name]
+		if !found || cfg.Hotspots[hs.Address].Hidden {
+			continue
+		}
+		hsSort = append(hsSort, order)
+		total += order.Reward
+	}
+	cfg.HsSort = hsSort
+	cfg.Total = total
+}
+
+// Reload replaces the tracked addresses with the ones from as and fetches
+// the hotspots again. The previous data is kept if fetching fails.
+func (cfg *config) Reload(ctx context.Context, as appSettings) error {
+	hsMap, err := fetchHotspots(ctx, as.AccountAddresses, as.HotspotAddresses)
+	if err != nil {
+		return err
+	}
+
+	// Settings, hotspots and rows change under one hold, so the view never
+	// sees groups or accounts it has no rows for yet
+	cfg.mu.Lock()
+	cfg.setSettings(as)
+	cfg.setHotspots(hsMap)
+	cfg.mu.Unlock()
+
+	cfg.SkipHotspotRefresh = true
+	return nil
+}
+
+func (cfg *config) applySettings(as appSettings) {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	cfg.setSettings(as)
+}
+
+// setSettings copies the settings of as. Callers hold cfg.mu.
+func (cfg *config) setSettings(as appSettings) {
+	cfg.AccountAddresses = as.AccountAddresses
+	cfg.HotspotAddresses = as.HotspotAddresses
+	cfg.RewardDays = settingsRewardDays(as)
+	cfg.RewardWindows = settingsRewardWindows(as)
+	cfg.Clock, _ = newDayClock(as.Timezone, as.DayMode)
+	cfg.Hotspots = as.Hotspots
+	cfg.SortBy = settingsSortBy(as)
+	cfg.Groups = as.Groups
+	cfg.TitleGroup = as.TitleGroup
+	cfg.Forecast = as.Forecast
+	cfg.AnomalySettings = as.Anomalies
+	cfg.SyncBlocks = as.SyncBlocks
+	if cfg.Nearby != as.Nearby {
+		cfg.NearbyAreas = make(map[string]nearbyArea)
+		cfg.NearbyFetched = time.Time{}
+	}
+	cfg.Nearby = as.Nearby
+	cfg.Explorer = as.Explorer
+	cfg.Settings = as
+}
+
+// Refresh runs a full data refresh cycle and updates the view. An error is
+// only returned when ctx is cancelled, in which case the view is left as is.
+func (cfg *config) Refresh(ctx context.Context) error {
+	if err := cfg.GetHNTPrice(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetAccounts(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetBlockHeight(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetPriceHistory(ctx); err != nil {
+		return err
+	}
+	if err := cfg.RefreshAllHotspots(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetHotspotRewards(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetHotspotActivity(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetRewardBreakdowns(ctx); err != nil {
+		return err
+	}
+	if err := cfg.GetNearby(ctx); err != nil {
+		return err
+	}
+
+	cfg.SortHotspots()
+	cfg.UpdateAlerts()
+	cfg.UpdateView()
+	cfg.SkipHotspotRefresh = false
+	return nil
+}
+
+func (cfg *config) GetHNTPrice(ctx context.Context) error {
 	// Get new HNT price for conversion
-	priceResp, err := getPrice()
+	priceResp, err := getPrice(ctx)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 	if err != nil {
 		msg := "Failed to get HNT price"
 		// Hard error on initial launch
@@ -69,153 +322,413 @@ func (cfg *config) GetHNTPrice() {
 			handleSoftError(err, msg)
 		}
 	} else {
+		cfg.mu.Lock()
 		cfg.Price = priceResp.Data.Price
+		cfg.mu.Unlock()
 	}
+	return nil
 }
 
-func (cfg *config) RefreshAllHotspots() {
+func (cfg *config) RefreshAllHotspots(ctx context.Context) error {
 	if !cfg.SkipHotspotRefresh {
 		for _, hs := range cfg.HsMap {
-			resp, err := getHotspot(hs.Address)
+			resp, err := getHotspot(ctx, hs.Address)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			if err != nil {
 				handleSoftError(err, "Failed to refresh hotspots")
 				continue
 			}
+
+			cfg.mu.Lock()
 			cfg.HsMap[hs.Name] = resp.Data
-			cfg.sleep()
+			cfg.mu.Unlock()
+			if err := cfg.sleep(ctx); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func (cfg *config) GetHotspotRewards() {
-	// Get rewards for each hotspot
+func (cfg *config) GetHotspotRewards(ctx context.Context) error {
+	var hsSort []sortOrder
+	var total bones
+	days := cfg.HistoryDays(cfg.Clock.Now())
+
+	// Get rewards for each hotspot that isn't hidden
 	for name, hs := range cfg.HsMap {
+		if cfg.Hotspots[hs.Address].Hidden {
+			continue
+		}
+
 		// Track rewards
-		rewardsResp, err := getHotspotRewards(hs.Address)
+		rewards, err := fetchRewards(ctx, hs.Address, days, cfg.Clock)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Hotspots without rewards are still listed so the menu can show n/a
 		if err != nil {
 			handleSoftError(err, "Failed to get rewards")
-			continue
-		} else {
-			cfg.HsRewards[name] = rewardsResp.Data
+		}
+		if rewards != nil {
+			cfg.mu.Lock()
+			cfg.HsRewards[name] = rewards
+			cfg.mu.Unlock()
 		}
 
 		// Track sorting order and today's reward
-		reward := cfg.RewardOn(name, 0)
-		cfg.HsSort = append(cfg.HsSort, sortOrder{Name: name, Reward: reward})
-		cfg.Total += reward
-		cfg.sleep()
+		reward, _ := cfg.RewardOn(name, 0)
+		hsSort = append(hsSort, sortOrder{Name: name, Reward: reward})
+		total += reward
+		if err := cfg.sleep(ctx); err != nil {
+			return err
+		}
 	}
+
+	// Swap in the new order only once every hotspot is done
+	cfg.mu.Lock()
+	cfg.HsSort = hsSort
+	cfg.Total = total
+	cfg.mu.Unlock()
+	return nil
 }
 
-func (cfg *config) SortHotspotsByReward() {
-	sort.SliceStable(cfg.HsSort, func(a, b int) bool {
-		return cfg.HsSort[a].Reward > cfg.HsSort[b].Reward
-	})
+// SetSortBy changes the sort mode and saves it to the config file
+func (cfg *config) SetSortBy(mode string) {
+	cfg.mu.Lock()
+	cfg.SortBy = mode
+	cfg.Settings.SortBy = mode
+	cfg.mu.Unlock()
+
+	cfg.SortHotspots()
+	cfg.UpdateView()
+	if err := saveAppSetting(appSettingsPath, "sort_by", mode); err != nil {
+		handleSoftError(err, "Failed to save config")
+	}
 }
 
-func (cfg *config) RewardOn(name string, day int) float64 {
-	return cfg.HsRewards[name][day].Total
+// HistoryDays returns the days of reward history needed to compare every
+// window against its previous period
+func (cfg *config) HistoryDays(now time.Time) int {
+	days := cfg.RewardDays
+	if lookback := cfg.Forecast.lookback() + 1; lookback > days {
+		days = lookback
+	}
+	if lookback := cfg.AnomalySettings.lookback() + recentDays + 1; lookback > days {
+		days = lookback
+	}
+	if since, found := cfg.earliestPurchase(); found {
+		if purchaseDays := int(now.Sub(since).Hours()/24) + 1; purchaseDays > days {
+			days = purchaseDays
+		}
+	}
+	for _, w := range cfg.RewardWindows {
+		if need := 2 * w.Length(now); need > days {
+			days = need
+		}
+	}
+	return days
 }
 
-func (cfg *config) RewardSum(name string, from int, length int) float64 {
-	partial := cfg.HsRewards[name][from:length]
-	result := float64(0)
-	for _, v := range partial {
-		result += v.Total
+// RewardOn returns the reward of a day, where 0 is the current day. It
+// returns false when there's no data for that day.
+func (cfg *config) RewardOn(name string, day int) (bones, bool) {
+	rewards := cfg.HsRewards[name]
+	if day < 0 || day >= len(rewards) {
+		return 0, false
 	}
-	return result
+	return rewards[day].Sum, true
+}
+
+// RewardSum sums the days from up to but not including to, limited to the
+// days with data. It also returns how many days were summed.
+func (cfg *config) RewardSum(name string, from int, to int) (bones, int) {
+	rewards := cfg.HsRewards[name]
+	if to > len(rewards) {
+		to = len(rewards)
+	}
+	if from < 0 || from >= to {
+		return 0, 0
+	}
+
+	result := bones(0)
+	for _, v := range rewards[from:to] {
+		result += v.Sum
+	}
+	return result, to - from
+}
+
+func (cfg *config) RewardDiff(name string, days int) rewardDiff {
+	current, currentDays := cfg.RewardSum(name, 0, days)
+	previous, previousDays := cfg.RewardSum(name, days, 2*days)
+	net, split := cfg.NetShare(name, current)
+	return rewardDiff{
+		Current:      current,
+		Previous:     previous,
+		Days:         days,
+		CurrentDays:  currentDays,
+		PreviousDays: previousDays,
+		Net:          net,
+		Split:        split,
+	}
+}
+
+// rewardDiffString formats a reward row, noting when a hotspot doesn't have
+// enough history to fill or compare the window
+func (cfg *config) rewardDiffString(label string, d rewardDiff) string {
+	if d.Split && d.CurrentDays > 0 {
+		return fmt.Sprintf("%s (net %s)", cfg.grossDiffString(label, d), cfg.rewardToString(d.Net))
+	}
+	return cfg.grossDiffString(label, d)
 }
 
-func (cfg *config) RewardDiff(name string, days int) (current float64, previous float64, diff float64) {
-	current = cfg.RewardSum(name, 0, days)
-	previous = cfg.RewardSum(name, days, 2*days)
-	diff = current - previous
-	return current, previous, current - previous
+func (cfg *config) grossDiffString(label string, d rewardDiff) string {
+	switch {
+	case d.CurrentDays == 0:
+		return fmt.Sprintf("%s - n/a", label)
+	case d.CurrentDays < d.Days:
+		return fmt.Sprintf("%s - %s (%d of %d days)", label, cfg.rewardToString(d.Current), d.CurrentDays, d.Days)
+	case !d.Comparable():
+		return fmt.Sprintf("%s - %s", label, cfg.rewardToString(d.Current))
+	default:
+		return fmt.Sprintf("%s - %s %s", label, cfg.rewardToString(d.Current), diffPercent(d.Diff(), d.Previous))
+	}
 }
 
-func (cfg *config) rewardToString(val float64) string {
+func (cfg *config) rewardToString(val bones) string {
 	var result string
 	if cfg.ConvertToDollars {
-		dollars := val * (float64(cfg.Price) / 100000000)
-		result = fmt.Sprintf("%s USD", floatToString(dollars))
+		result = fmt.Sprintf("%s USD", val.ToUSD(cfg.Price))
 	} else {
-		result = fmt.Sprintf("%s HNT", floatToString(val))
+		result = fmt.Sprintf("%s HNT", val)
 	}
 	return result
 }
 
+func (cfg *config) SetConvertToDollars(convert bool) {
+	cfg.mu.Lock()
+	cfg.ConvertToDollars = convert
+	cfg.mu.Unlock()
+	cfg.UpdateView()
+}
+
 func (cfg *config) UpdateView() {
-	for i, order := range cfg.HsSort {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	now := cfg.Clock.Now()
+	stats := cfg.fleetStatsByPeriod()
+
+	// Split rows between groups, keeping the sort order within each
+	var ungrouped []sortOrder
+	members := make([][]sortOrder, len(cfg.Groups))
+	for _, order := range cfg.HsSort {
+		if g := cfg.GroupIndex(cfg.HsMap[order.Name].Address); g >= 0 {
+			members[g] = append(members[g], order)
+		} else {
+			ungrouped = append(ungrouped, order)
+		}
+	}
+
+	for g, group := range cfg.Groups {
+		item := cfg.GroupMenuItems[g]
+		cfg.updateGroup(item, group.Name, members[g], now)
+		cfg.updateRows(item.Rows, members[g], stats, now)
+		item.MenuItem.Show()
+	}
+
+	// Hide groups no longer in the config
+	for g := len(cfg.Groups); g < len(cfg.GroupMenuItems); g++ {
+		cfg.GroupMenuItems[g].MenuItem.Hide()
+		cfg.updateRows(cfg.GroupMenuItems[g].Rows, nil, stats, now)
+	}
+
+	cfg.updateRows(cfg.HsMenuItems, ungrouped, stats, now)
+	cfg.updateAccounts(now)
+	cfg.updateAnalytics(stats)
+	if cfg.ForecastMenuItem != nil {
+		cfg.ForecastMenuItem.SetTitle(cfg.outlookString(cfg.FleetOutlook(now)))
+	}
+	cfg.updateAlertsMenu()
+
+	// update title with total, marked when anything needs a look
+	mark := ""
+	if len(cfg.Alerts) > 0 {
+		mark = alertMark
+	}
+	if g := cfg.groupByName(cfg.TitleGroup); g >= 0 {
+		total := bones(0)
+		for _, order := range members[g] {
+			total += order.Reward
+		}
+		systray.SetTitle(fmt.Sprintf("%s%s: %s", mark, cfg.TitleGroup, cfg.rewardToString(total)))
+	} else {
+		systray.SetTitle(mark + cfg.rewardToString(cfg.Total))
+	}
+}
+
+// updateRows shows orders in rows and hides the rows left over. Orders
+// without a row, such as hotspots added by a reload that hasn't finished
+// its refresh, are left out.
+func (cfg *config) updateRows(rows []*hotspotMenuItem, orders []sortOrder, stats []fleetStats, now time.Time) {
+	if len(orders) > len(rows) {
+		orders = orders[:len(rows)]
+	}
+	for i, order := range orders {
+		row := rows[i]
 		hs := cfg.HsMap[order.Name]
-		onlineStatus := hs.Status.Online
+		onlineStatus := cfg.HotspotStatus(order.Name)
 		scale := hs.RewardScale
 
 		// Update status of each hotspot row
-		r24H, p24H, d24H := cfg.RewardDiff(order.Name, 1)
-		setStatus(cfg.HsMenuItems[i].MenuItem, onlineStatus, d24H)
-		cfg.HsMenuItems[i].MenuItem.SetTitle(fmt.Sprintf("%s - %s", cfg.rewardToString(r24H), order.Name))
+		r24H := cfg.RewardDiff(order.Name, 1)
+		setStatus(row.MenuItem, onlineStatus, r24H.Diff())
+		mark := ""
+		if cfg.hasAlert(hs.Address) {
+			mark = alertMark
+		}
+		if r24H.CurrentDays == 0 {
+			row.MenuItem.SetTitle(fmt.Sprintf("%sn/a - %s", mark, cfg.DisplayName(order.Name)))
+		} else {
+			row.MenuItem.SetTitle(fmt.Sprintf("%s%s - %s", mark, cfg.rewardToString(r24H.Current), cfg.DisplayName(order.Name)))
+		}
 
 		// Populate sub-menu
-		cfg.HsMenuItems[i].Status.SetTitle(fmt.Sprintf("Status: %s", onlineStatus))
-		cfg.HsMenuItems[i].Scale.SetTitle(fmt.Sprintf("Reward scale: %s", floatToString(scale)))
+		row.Status.SetTitle(cfg.statusString(order.Name))
+		row.Scale.SetTitle(fmt.Sprintf("Reward scale: %s", floatToString(scale)))
+		if flags := cfg.Flags(order.Name); len(flags) > 0 {
+			row.Anomaly.SetTitle(flagString(flags))
+			row.Anomaly.Show()
+		} else {
+			row.Anomaly.Hide()
+		}
 
-		r24HRow := cfg.HsMenuItems[i].R24H
-		setStatus(r24HRow, onlineStatus, d24H)
-		r24HRow.SetTitle(fmt.Sprintf("24H - %s %s", cfg.rewardToString(r24H), diffPercent(d24H, p24H)))
+		rewardRows := row.RewardRows(cfg.RewardWindows)
+		for j, w := range cfg.RewardWindows {
+			d := cfg.RewardDiff(order.Name, w.Length(now))
+			setStatus(rewardRows[j], onlineStatus, d.Diff())
+			rewardRows[j].SetTitle(cfg.rewardDiffString(w.Label(), d))
+		}
 
-		r07dRow := cfg.HsMenuItems[i].R07D
-		r07d, p07D, d07D := cfg.RewardDiff(order.Name, 7)
-		setStatus(r07dRow, onlineStatus, d07D)
-		r07dRow.SetTitle(fmt.Sprintf("07D - %s %s", cfg.rewardToString(r07d), diffPercent(d07D, p07D)))
+		row.Forecast.SetTitle(cfg.outlookString(cfg.HotspotOutlook(order.Name, now)))
+		row.Fleet.SetTitle(cfg.relativeString(order.Name, stats))
+		if cfg.Nearby != "" {
+			row.Nearby.SetTitle(cfg.nearbyString(order.Name))
+			row.Nearby.Show()
+		} else {
+			row.Nearby.Hide()
+		}
+		cfg.updateROI(row.ROI, order.Name, now)
+		cfg.updateActivity(row.Activity, order.Name, now)
+		cfg.updateBreakdown(row.Breakdown, order.Name, now)
+		cfg.updateDetails(row.Details, order.Name, now)
+
+		// Set buttons for opening hotspot in explorers
+		row.Explorers = syncExplorerRows(row.MenuItem, row.Explorers, cfg.Explorer.hotspotLinks())
+		cfg.registerRow(row, order.Name, now)
+		row.MenuItem.Show()
+	}
 
-		r30DRow := cfg.HsMenuItems[i].R30D
-		r30D, p30D, d30D := cfg.RewardDiff(order.Name, 30)
-		setStatus(r30DRow, onlineStatus, d30D)
-		r30DRow.SetTitle(fmt.Sprintf("30D - %s %s", cfg.rewardToString(r30D), diffPercent(d30D, p30D)))
+	// Hide rows left over from hotspots that are no longer tracked
+	for i := len(orders); i < len(rows); i++ {
+		rows[i].unregister()
+		rows[i].MenuItem.Hide()
+	}
+}
 
-		// Set button for opening hotspot in Helium explorer
-		cfg.HsMenuItems[i].Explorer.SetTitle("Open Helium explorer...")
+// registerRow ties the actions of a row to the hotspot it shows
+func (cfg *config) registerRow(row *hotspotMenuItem, name string, now time.Time) {
+	links := cfg.Explorer.hotspotLinks()
+	for i, item := range row.Explorers {
+		if i < len(links) {
+			events.Register(item, openURLAction(cfg.hotspotURL(name, i)))
+		} else {
+			events.Unregister(item)
+		}
 	}
 
-	// update title with total
-	systray.SetTitle(cfg.rewardToString(cfg.Total))
+	events.Register(row.Copy.Address, copyAction(cfg.HsMap[name].Address))
+	events.Register(row.Copy.URL, copyAction(cfg.hotspotURL(name, 0)))
+	events.Register(row.Copy.Summary, copyAction(cfg.SummaryLine(name, now)))
 }
 
-func (cfg *config) ClearPreviousData() {
-	cfg.Total = 0.0
-	cfg.HsSort = []sortOrder{}
-}
+func (cfg *config) sleep(ctx context.Context) error {
+	cfg.mu.Lock()
+	count := len(cfg.HsMap)
+	cfg.mu.Unlock()
 
-func (cfg *config) sleep() {
-	time.Sleep(time.Duration(10*len(cfg.HsMap)) * time.Millisecond)
+	timer := time.NewTimer(time.Duration(10*count) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
-func newConfig(as appSettings) config {
-	return config{
-		AccountAddresses: as.AccountAddresses,
-		HotspotAddresses: as.HotspotAddresses,
+func newConfig(as appSettings) *config {
+	cfg := &config{
 		HsMap:            make(map[string]hotspot),
+		Accounts:         make(map[string]account),
 		HsRewards:        make(map[string][]reward),
-		HsMenuItems:      []hotspotMenuItem{},
+		HsActivity:       make(map[string]hotspotActivity),
+		HsBreakdown:      make(map[string][]rewardEntry),
+		NearbyAreas:      make(map[string]nearbyArea),
+		HsMenuItems:      []*hotspotMenuItem{},
 		HsSort:           []sortOrder{},
 		ConvertToDollars: false,
 	}
+	cfg.applySettings(as)
+	return cfg
 }
 
-func setStatus(mi *systray.MenuItem, status string, diff float64) {
+// settingsRewardDays returns the configured reward history, never less than
+// the default rewardDays
+func settingsRewardDays(as appSettings) int {
+	if as.RewardDays > rewardDays {
+		return as.RewardDays
+	}
+	return rewardDays
+}
+
+func settingsRewardWindows(as appSettings) []rewardWindow {
+	if len(as.RewardWindows) == 0 {
+		return defaultRewardWindows
+	}
+	return as.RewardWindows
+}
+
+func settingsSortBy(as appSettings) string {
+	if as.SortBy == "" {
+		return sortByReward
+	}
+	return as.SortBy
+}
+
+func setStatus(mi *systray.MenuItem, status string, diff bones) {
 	var currentIcon []byte
 	switch {
-	case status == "online" && diff == 0:
+	case status == statusOnline && diff == 0:
 		currentIcon = icon.StatusPos
-	case status == "online" && diff > 0:
+	case status == statusOnline && diff > 0:
 		currentIcon = icon.StatusPosUp
-	case status == "online" && diff < 0:
+	case status == statusOnline && diff < 0:
 		currentIcon = icon.StatusPosDown
-	case status != "online" && diff == 0:
+	case status == statusSyncing && diff == 0:
+		currentIcon = icon.StatusSync
+	case status == statusSyncing && diff > 0:
+		currentIcon = icon.StatusSyncUp
+	case status == statusSyncing && diff < 0:
+		currentIcon = icon.StatusSyncDown
+	case status != statusOnline && diff == 0:
 		currentIcon = icon.StatusErr
-	case status != "online" && diff > 0:
+	case status != statusOnline && diff > 0:
 		currentIcon = icon.StatusErrUp
-	case status != "online" && diff < 0:
+	case status != statusOnline && diff < 0:
 		currentIcon = icon.StatusErrDown
 	}
 	mi.SetIcon(currentIcon)
@@ -225,19 +738,23 @@ func floatToString(val float64) string {
 	return fmt.Sprintf("%.2f", val)
 }
 
-func diffPercent(diff float64, prev float64) string {
-	percent := (diff / prev) * 100
-	var prefix string
+func diffPercent(diff bones, prev bones) string {
+	if change := percentChange(diff, prev); change != "" {
+		return "/ " + change
+	}
+	return ""
+}
+
+// percentChange formats diff as a signed percentage of prev, or returns an
+// empty string when prev is 0. Reward rows and copied summaries share it.
+func percentChange(diff bones, prev bones) string {
+	percent := (diff.HNT() / prev.HNT()) * 100
 	switch {
-	case math.IsInf(percent, 0):
-		return ""
-	case math.IsNaN(percent):
+	case math.IsInf(percent, 0), math.IsNaN(percent):
 		return ""
 	case percent > 0:
-		prefix = "/ +"
+		return fmt.Sprintf("+%s%%", floatToString(percent))
 	default:
-		prefix = "/ "
+		return fmt.Sprintf("%s%%", floatToString(percent))
 	}
-
-	return fmt.Sprintf("%s%s%%", prefix, floatToString(percent))
 }
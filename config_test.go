@@ -0,0 +1,157 @@
+package main
+
+import "testing"
+
+// history returns daily rewards newest first, in whole HNT
+func history(hnt ...int64) []reward {
+	var rewards []reward
+	for _, v := range hnt {
+		rewards = append(rewards, reward{Sum: bones(v * unitsPerWhole)})
+	}
+	return rewards
+}
+
+func hnt(v int64) bones {
+	return bones(v * unitsPerWhole)
+}
+
+func testConfig(rewards []reward) *config {
+	cfg := newConfig(appSettings{})
+	if rewards != nil {
+		cfg.HsRewards["hs"] = rewards
+	}
+	return cfg
+}
+
+func TestRewardOn(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewards []reward
+		day     int
+		want    bones
+		found   bool
+	}{
+		{"nil history", nil, 0, 0, false},
+		{"1 day today", history(3), 0, hnt(3), true},
+		{"1 day yesterday", history(3), 1, 0, false},
+		{"negative day", history(3), -1, 0, false},
+		{"6 days last", history(1, 2, 3, 4, 5, 6), 5, hnt(6), true},
+		{"6 days past end", history(1, 2, 3, 4, 5, 6), 6, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := testConfig(tt.rewards).RewardOn("hs", tt.day)
+			if got != tt.want || found != tt.found {
+				t.Errorf("RewardOn(%d) = %v, %v, want %v, %v", tt.day, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestRewardSum(t *testing.T) {
+	tests := []struct {
+		name     string
+		rewards  []reward
+		from, to int
+		want     bones
+		wantDays int
+	}{
+		{"nil history", nil, 0, 7, 0, 0},
+		{"1 day", history(3), 0, 7, hnt(3), 1},
+		{"1 day previous period", history(3), 7, 14, 0, 0},
+		{"6 days of 7", history(1, 1, 1, 1, 1, 1), 0, 7, hnt(6), 6},
+		{"6 days previous period", history(1, 1, 1, 1, 1, 1), 7, 14, 0, 0},
+		{"2x7 days current", history(1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2), 0, 7, hnt(7), 7},
+		{"2x7 days previous", history(1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2), 7, 14, hnt(14), 7},
+		{"empty range", history(1, 1), 1, 1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, days := testConfig(tt.rewards).RewardSum("hs", tt.from, tt.to)
+			if got != tt.want || days != tt.wantDays {
+				t.Errorf("RewardSum(%d, %d) = %v, %d, want %v, %d", tt.from, tt.to, got, days, tt.want, tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestRewardDiff(t *testing.T) {
+	tests := []struct {
+		name       string
+		rewards    []reward
+		days       int
+		want       rewardDiff
+		comparable bool
+		diff       bones
+	}{
+		{"nil history", nil, 7, rewardDiff{Days: 7}, false, 0},
+		{"1 day", history(3), 7, rewardDiff{Current: hnt(3), Net: hnt(3), Days: 7, CurrentDays: 1}, false, 0},
+		{"6 days of 7", history(1, 1, 1, 1, 1, 1), 7,
+			rewardDiff{Current: hnt(6), Net: hnt(6), Days: 7, CurrentDays: 6}, false, 0},
+		{"2x7 days", history(2, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1), 7,
+			rewardDiff{Current: hnt(14), Previous: hnt(7), Net: hnt(14), Days: 7, CurrentDays: 7, PreviousDays: 7}, true, hnt(7)},
+		{"2x1 days", history(1, 3), 1,
+			rewardDiff{Current: hnt(1), Previous: hnt(3), Net: hnt(1), Days: 1, CurrentDays: 1, PreviousDays: 1}, true, hnt(-2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testConfig(tt.rewards).RewardDiff("hs", tt.days)
+			if got != tt.want {
+				t.Errorf("RewardDiff(%d) = %+v, want %+v", tt.days, got, tt.want)
+			}
+			if got.Comparable() != tt.comparable || got.Diff() != tt.diff {
+				t.Errorf("Comparable() = %v, Diff() = %v, want %v, %v", got.Comparable(), got.Diff(), tt.comparable, tt.diff)
+			}
+		})
+	}
+}
+
+func TestRewardDiffAdd(t *testing.T) {
+	full := rewardDiff{Current: hnt(7), Previous: hnt(7), Net: hnt(7), Days: 7, CurrentDays: 7, PreviousDays: 7}
+	tests := []struct {
+		name string
+		a, b rewardDiff
+		want rewardDiff
+	}{
+		{"both without data", rewardDiff{Days: 7}, rewardDiff{Days: 7}, rewardDiff{Days: 7}},
+		{"new hotspot without data", full, rewardDiff{Days: 7}, full},
+		{"first without data", rewardDiff{Days: 7}, full, full},
+		{"new hotspot with 1 day", full, rewardDiff{Current: hnt(1), Net: hnt(1), Days: 7, CurrentDays: 1},
+			rewardDiff{Current: hnt(8), Previous: hnt(7), Net: hnt(8), Days: 7, CurrentDays: 1}},
+		{"new hotspot with 6 days", full, rewardDiff{Current: hnt(6), Net: hnt(3), Split: true, Days: 7, CurrentDays: 6},
+			rewardDiff{Current: hnt(13), Previous: hnt(7), Net: hnt(10), Split: true, Days: 7, CurrentDays: 6}},
+		{"both full", full, full,
+			rewardDiff{Current: hnt(14), Previous: hnt(14), Net: hnt(14), Days: 7, CurrentDays: 7, PreviousDays: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Add(tt.b); got != tt.want {
+				t.Errorf("Add() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrossDiffString(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewards []reward
+		days    int
+		want    string
+	}{
+		{"nil history", nil, 7, "07D - n/a"},
+		{"1 day", history(3), 7, "07D - 3.00 HNT (1 of 7 days)"},
+		{"6 days of 7", history(1, 1, 1, 1, 1, 1), 7, "07D - 6.00 HNT (6 of 7 days)"},
+		{"7 days without previous", history(1, 1, 1, 1, 1, 1, 1), 7, "07D - 7.00 HNT"},
+		{"2x7 days", history(2, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1), 7, "07D - 14.00 HNT / +100.00%"},
+		{"2x7 days down", history(1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2), 7, "07D - 7.00 HNT / -50.00%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(tt.rewards)
+			if got := cfg.grossDiffString("07D", cfg.RewardDiff("hs", tt.days)); got != tt.want {
+				t.Errorf("grossDiffString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
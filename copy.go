@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+type copyMenuItem struct {
+	MenuItem *systray.MenuItem
+	Address  *systray.MenuItem
+	URL      *systray.MenuItem
+	Summary  *systray.MenuItem
+}
+
+func newCopyMenuItem(parent *systray.MenuItem) *copyMenuItem {
+	item := parent.AddSubMenuItem("Copy", "Copy to clipboard")
+	return &copyMenuItem{
+		MenuItem: item,
+		Address:  item.AddSubMenuItem("Address", "Copy the hotspot address"),
+		URL:      item.AddSubMenuItem("Explorer URL", "Copy the hotspot explorer URL"),
+		Summary:  item.AddSubMenuItem("Summary", "Copy a one line summary of rewards"),
+	}
+}
+
+// copyAction copies value when its item is clicked
+func copyAction(value string) func() {
+	return func() {
+		if err := copyToClipboard(value); err != nil {
+			handleSoftError(err, "Failed to copy to clipboard")
+		}
+	}
+}
+
+// SummaryLine sums up a hotspot's reward windows on one line, like
+// "name: 24H 0.10 HNT (+5.00%) / 07D 0.80 HNT (-2.00%)"
+func (cfg *config) SummaryLine(name string, now time.Time) string {
+	var windows []string
+	for _, w := range cfg.RewardWindows {
+		windows = append(windows, fmt.Sprintf("%s %s", w.Label(), cfg.windowSummary(cfg.RewardDiff(name, w.Length(now)))))
+	}
+	return fmt.Sprintf("%s: %s", cfg.DisplayName(name), strings.Join(windows, " / "))
+}
+
+// windowSummary is the reward of a window and its change, if comparable
+func (cfg *config) windowSummary(d rewardDiff) string {
+	if d.CurrentDays == 0 {
+		return "n/a"
+	}
+	change := percentChange(d.Diff(), d.Previous)
+	if !d.Comparable() || change == "" {
+		return cfg.rewardToString(d.Current)
+	}
+	return fmt.Sprintf("%s (%s)", cfg.rewardToString(d.Current), change)
+}
+
+// FleetMarkdown tabulates every hotspot row with its status and reward
+// windows as a Markdown table
+func (cfg *config) FleetMarkdown(now time.Time) string {
+	var b strings.Builder
+	header := []string{"Hotspot", "Status"}
+	for _, w := range cfg.RewardWindows {
+		header = append(header, w.Label())
+	}
+	writeMarkdownRow(&b, header)
+
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+		if i > 1 {
+			separator[i] = "---:"
+		}
+	}
+	writeMarkdownRow(&b, separator)
+
+	for _, order := range cfg.HsSort {
+		row := []string{cfg.DisplayName(order.Name), cfg.HotspotStatus(order.Name)}
+		for _, w := range cfg.RewardWindows {
+			row = append(row, cfg.windowSummary(cfg.RewardDiff(order.Name, w.Length(now))))
+		}
+		writeMarkdownRow(&b, row)
+	}
+
+	total := []string{"**Total**", ""}
+	for _, w := range cfg.RewardWindows {
+		total = append(total, cfg.windowSummary(cfg.GroupDiff(cfg.HsSort, w.Length(now))))
+	}
+	writeMarkdownRow(&b, total)
+	return b.String()
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(cell, "|", "\\|")
+	}
+	fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+}
+
+// CopyFleetSummary copies the Markdown table of every hotspot row
+func (cfg *config) CopyFleetSummary() error {
+	cfg.mu.Lock()
+	table := cfg.FleetMarkdown(cfg.Clock.Now())
+	cfg.mu.Unlock()
+	return copyToClipboard(table)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// withClipboard swaps appClipboard for a buffer for the length of a test
+func withClipboard(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	prev := appClipboard
+	appClipboard = writerClipboard{w: &buf}
+	t.Cleanup(func() { appClipboard = prev })
+	return &buf
+}
+
+func TestCopyAction(t *testing.T) {
+	buf := withClipboard(t)
+	copyAction("112abc")()
+	if got := buf.String(); got != "112abc\n" {
+		t.Errorf("copied %q, want %q", got, "112abc\n")
+	}
+}
+
+func TestCopyFleetSummary(t *testing.T) {
+	buf := withClipboard(t)
+	cfg := testConfig(history(2, 1))
+	cfg.RewardWindows = []rewardWindow{{Spec: "1d", Days: 1}, {Spec: "7d", Days: 7}}
+	hs := hotspot{Name: "hs", Address: "112abc"}
+	hs.Status.Online = statusOnline
+	cfg.HsMap["hs"] = hs
+	cfg.HsSort = []sortOrder{{Name: "hs", Reward: hnt(2)}}
+
+	if err := cfg.CopyFleetSummary(); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"| Hotspot | Status | 24H | 07D |",
+		"| --- | --- | ---: | ---: |",
+		"| hs | online | 2.00 HNT (+100.00%) | 3.00 HNT |",
+		"| **Total** |  | 2.00 HNT (+100.00%) | 3.00 HNT |",
+		"",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("copied\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestCopiedPercentMatchesMenu checks the copied summary shows the same
+// change as the reward row in the menu
+func TestCopiedPercentMatchesMenu(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewards []reward
+	}{
+		{"up", history(3, 2)},
+		{"down", history(1, 3)},
+		{"unchanged", history(2, 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(tt.rewards)
+			d := cfg.RewardDiff("hs", 1)
+			change := percentChange(d.Diff(), d.Previous)
+			if menu := cfg.grossDiffString("24H", d); !strings.HasSuffix(menu, "/ "+change) {
+				t.Errorf("menu row %q doesn't end with %q", menu, change)
+			}
+			if copied := cfg.windowSummary(d); !strings.HasSuffix(copied, "("+change+")") {
+				t.Errorf("copied summary %q doesn't end with %q", copied, change)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+// detail is a row of hotspot details and the value copied when it's clicked
+type detail struct {
+	Title string
+	Value string // empty when there's nothing to copy
+}
+
+// detailRows is the number of rows Details returns
+const detailRows = 8
+
+type detailsMenuItem struct {
+	MenuItem *systray.MenuItem
+	Rows     []*systray.MenuItem
+}
+
+func newDetailsMenuItem(parent *systray.MenuItem) *detailsMenuItem {
+	item := parent.AddSubMenuItem("Details", "Click a detail to copy it")
+	details := &detailsMenuItem{MenuItem: item}
+	for i := 0; i < detailRows; i++ {
+		details.Rows = append(details.Rows, item.AddSubMenuItem("Loading...", "Copy to clipboard"))
+	}
+	return details
+}
+
+// Details describes where a hotspot is, how it's set up and its history
+func (cfg *config) Details(name string, now time.Time) []detail {
+	hs := cfg.HsMap[name]
+	details := []detail{
+		valueDetail("City", joinNonEmpty(hs.Geocode.LongCity, hs.Geocode.LongState, hs.Geocode.LongCountry)),
+		valueDetail("Street", hs.Geocode.LongStreet),
+	}
+
+	// Gain is reported in tenths of a dBi
+	gain := strconv.FormatFloat(float64(hs.Gain)/10, 'f', -1, 64)
+	details = append(details,
+		detail{fmt.Sprintf("Antenna gain: %s dBi", gain), gain},
+		detail{fmt.Sprintf("Elevation: %d m", hs.Elevation), strconv.Itoa(hs.Elevation)},
+		valueDetail("Mode", modeString(hs.Mode)))
+
+	if hs.TimestampAdded.IsZero() {
+		details = append(details, detail{"Age: n/a", ""})
+	} else {
+		added := hs.TimestampAdded.In(cfg.Clock.Location).Format(dateFormat)
+		age := int(now.Sub(hs.TimestampAdded) / day)
+		details = append(details, detail{fmt.Sprintf("Age: %d days (added %s)", age, added), added})
+	}
+
+	if hs.Owner == "" {
+		details = append(details, detail{"Owner: n/a", ""})
+	} else {
+		details = append(details, detail{fmt.Sprintf("Owner: %s", shortAddress(hs.Owner)), hs.Owner})
+	}
+
+	if cfg.Height == 0 || hs.LastPocChallenge == 0 {
+		details = append(details, detail{"Last PoC challenge: n/a", ""})
+	} else {
+		blocks := strconv.Itoa(cfg.Height - hs.LastPocChallenge)
+		details = append(details, detail{fmt.Sprintf("Last PoC challenge: %s blocks ago", blocks), blocks})
+	}
+	return details
+}
+
+func valueDetail(title string, value string) detail {
+	if value == "" {
+		return detail{title + ": n/a", ""}
+	}
+	return detail{fmt.Sprintf("%s: %s", title, value), value}
+}
+
+func joinNonEmpty(values ...string) string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return strings.Join(result, ", ")
+}
+
+func modeString(mode string) string {
+	if mode == "dataonly" {
+		return "data-only"
+	}
+	return mode
+}
+
+func (cfg *config) updateDetails(item *detailsMenuItem, name string, now time.Time) {
+	for i, d := range cfg.Details(name, now) {
+		item.Rows[i].SetTitle(d.Title)
+		if d.Value == "" {
+			events.Unregister(item.Rows[i])
+			item.Rows[i].Disable()
+		} else {
+			events.Register(item.Rows[i], copyAction(d.Value))
+			item.Rows[i].Enable()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/getlantern/systray"
+)
+
+// events routes menu clicks to the actions registered for them
+var events = newDispatcher()
+
+// dispatcher ties menu items to actions. Actions are bound to what a row
+// shows when it's updated, such as a hotspot address, so a click always
+// acts on what was on screen even after rows are re-sorted or added.
+type dispatcher struct {
+	mu        sync.Mutex
+	actions   map[*systray.MenuItem]func()
+	listening map[*systray.MenuItem]bool
+	clicks    chan *systray.MenuItem
+}
+
+func newDispatcher() *dispatcher {
+	return &dispatcher{
+		actions:   make(map[*systray.MenuItem]func()),
+		listening: make(map[*systray.MenuItem]bool),
+		clicks:    make(chan *systray.MenuItem),
+	}
+}
+
+// Register runs action when item is clicked, replacing any earlier action
+func (d *dispatcher) Register(item *systray.MenuItem, action func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.actions[item] = action
+	if !d.listening[item] {
+		d.listening[item] = true
+		// Forwarders only block on channels and stop with the app, so they
+		// aren't waited for on exit
+		go d.forward(item)
+	}
+}
+
+// Unregister ignores clicks on item until it's registered again
+func (d *dispatcher) Unregister(item *systray.MenuItem) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.actions, item)
+}
+
+func (d *dispatcher) forward(item *systray.MenuItem) {
+	for {
+		select {
+		case <-item.ClickedCh:
+			select {
+			case d.clicks <- item:
+			case <-appCtx.Done():
+				return
+			}
+		case <-appCtx.Done():
+			return
+		}
+	}
+}
+
+// Run calls the action of each clicked item, one at a time, until ctx is
+// done. Actions that take a while should start their own routine.
+func (d *dispatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case item := <-d.clicks:
+			d.mu.Lock()
+			action := d.actions[item]
+			d.mu.Unlock()
+			if action != nil {
+				action()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/getlantern/systray"
+	"github.com/pkg/browser"
+)
+
+const (
+	defaultHotspotURL = "https://explorer.helium.com/hotspots/{address}"
+	defaultAccountURL = "https://explorer.helium.com/accounts/{address}"
+)
+
+// urlTemplate is a named link with {address}, {name} and {hex} placeholders
+type urlTemplate struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type explorerSettings struct {
+	Hotspots []urlTemplate `json:"hotspots,omitempty"` // links in each hotspot's sub-menu
+	Account  string        `json:"account,omitempty"`  // link of each account
+}
+
+func (s explorerSettings) validate() error {
+	for _, t := range s.Hotspots {
+		if t.Name == "" || !validTemplate(t.URL) {
+			return errors.New("Invalid hotspot explorer link")
+		}
+	}
+	if s.Account != "" && !validTemplate(s.Account) {
+		return errors.New("Invalid account explorer link")
+	}
+	return nil
+}
+
+func validTemplate(template string) bool {
+	u, err := url.Parse(expandTemplate(template, "address", "name", "hex"))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (s explorerSettings) hotspotLinks() []urlTemplate {
+	if len(s.Hotspots) == 0 {
+		return []urlTemplate{{Name: "Helium explorer", URL: defaultHotspotURL}}
+	}
+	return s.Hotspots
+}
+
+func (s explorerSettings) accountURL() string {
+	if s.Account == "" {
+		return defaultAccountURL
+	}
+	return s.Account
+}
+
+// expandTemplate fills in the placeholders of a URL template
+func expandTemplate(template string, address string, name string, hex string) string {
+	return strings.NewReplacer(
+		"{address}", url.PathEscape(address),
+		"{name}", url.PathEscape(name),
+		"{hex}", url.PathEscape(hex),
+	).Replace(template)
+}
+
+// syncExplorerRows adds rows to parent when the links changed on a config
+// reload and hides the ones no longer used. It returns a row per link.
+func syncExplorerRows(parent *systray.MenuItem, rows []*systray.MenuItem, links []urlTemplate) []*systray.MenuItem {
+	for len(rows) < len(links) {
+		rows = append(rows, parent.AddSubMenuItem("Loading...", "Open hotspot in "+links[len(rows)].Name))
+	}
+	for i, item := range rows {
+		if i < len(links) {
+			item.SetTitle("Open " + links[i].Name + "...")
+			item.Show()
+		} else {
+			item.Hide()
+		}
+	}
+	return rows
+}
+
+// hotspotURL returns the URL of link i of a hotspot
+func (cfg *config) hotspotURL(name string, i int) string {
+	hs := cfg.HsMap[name]
+	return expandTemplate(cfg.Explorer.hotspotLinks()[i].URL, hs.Address, name, hs.LocationHex)
+}
+
+func (cfg *config) accountURL(address string) string {
+	return expandTemplate(cfg.Explorer.accountURL(), address, "", "")
+}
+
+// AccountURL returns the URL of an account
+func (cfg *config) AccountURL(address string) string {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	return cfg.accountURL(address)
+}
+
+func openURLAction(url string) func() {
+	return func() {
+		browser.OpenURL(url)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Forecast models
+const (
+	forecastTrailing = "trailing" // average of the lookback days
+	forecastEWMA     = "ewma"     // exponentially weighted average, recent days count more
+	forecastTrend    = "trend"    // least squares linear trend
+)
+
+const (
+	defaultLookback = 30   // days
+	forecastZ       = 1.96 // 95% confidence band
+)
+
+type forecastSettings struct {
+	Model    string `json:"model,omitempty"`
+	Lookback int    `json:"lookback,omitempty"` // completed days the model learns from
+}
+
+func (s forecastSettings) validate() error {
+	switch s.Model {
+	case "", forecastTrailing, forecastEWMA, forecastTrend:
+	default:
+		return errors.New("Invalid forecast model")
+	}
+	if s.Lookback < 0 {
+		return errors.New("Invalid forecast lookback")
+	}
+	return nil
+}
+
+func (s forecastSettings) lookback() int {
+	if s.Lookback == 0 {
+		return defaultLookback
+	}
+	return s.Lookback
+}
+
+// projection is the expected reward over a number of days with a band
+// around it
+type projection struct {
+	Expected bones
+	Band     bones // half width of the confidence band
+}
+
+// forecaster fits a model to a daily series, oldest day first
+type forecaster struct {
+	model  string
+	series []float64
+	level  float64 // expected reward of the next day
+	slope  float64 // change per day, for the trend model
+	stddev float64 // of the daily residuals
+}
+
+func newForecaster(model string, series []float64) forecaster {
+	f := forecaster{model: model, series: series}
+	n := float64(len(series))
+	if n == 0 {
+		return f
+	}
+
+	switch model {
+	case forecastEWMA:
+		alpha := 2 / (n + 1)
+		f.level = series[0]
+		for _, v := range series[1:] {
+			f.level = alpha*v + (1-alpha)*f.level
+		}
+	case forecastTrend:
+		// Fit v = a + b*t with t = 0 for the oldest day
+		var sumT, sumV, sumTT, sumTV float64
+		for t, v := range series {
+			sumT += float64(t)
+			sumV += v
+			sumTT += float64(t * t)
+			sumTV += float64(t) * v
+		}
+		if denom := n*sumTT - sumT*sumT; denom != 0 {
+			f.slope = (n*sumTV - sumT*sumV) / denom
+		}
+		intercept := (sumV - f.slope*sumT) / n
+		f.level = intercept + f.slope*n
+	default:
+		for _, v := range series {
+			f.level += v
+		}
+		f.level /= n
+	}
+
+	// Residuals against the fitted values
+	var sq float64
+	for t, v := range series {
+		fitted := f.level
+		if model == forecastTrend {
+			fitted = f.level + f.slope*(float64(t)-n)
+		}
+		sq += (v - fitted) * (v - fitted)
+	}
+	if n > 1 {
+		f.stddev = math.Sqrt(sq / (n - 1))
+	}
+	return f
+}
+
+// Project sums the expected rewards of the next days, starting with the
+// current day. Daily noise is assumed independent, so the band grows with
+// the square root of the days.
+func (f forecaster) Project(days int) projection {
+	var expected float64
+	for d := 0; d < days; d++ {
+		expected += math.Max(0, f.level+f.slope*float64(d))
+	}
+	band := forecastZ * f.stddev * math.Sqrt(float64(days))
+	return projection{Expected: bones(math.Round(expected)), Band: bones(math.Round(band))}
+}
+
+// dailySeries returns the completed days of the lookback, oldest first
+func (cfg *config) dailySeries(name string, lookback int) []float64 {
+	rewards := cfg.HsRewards[name]
+	var series []float64
+	for i := lookback; i >= 1; i-- {
+		if i < len(rewards) {
+			series = append(series, float64(rewards[i].Sum))
+		}
+	}
+	return series
+}
+
+// fleetSeries sums the daily series of every row, so the total forecast
+// keeps days when the whole fleet moved together
+func (cfg *config) fleetSeries(lookback int) []float64 {
+	var fleet []float64
+	for _, order := range cfg.HsSort {
+		series := cfg.dailySeries(order.Name, lookback)
+		// Align newest days, hotspots with less history start later
+		offset := lookback - len(series)
+		for len(fleet) < lookback {
+			fleet = append(fleet, 0)
+		}
+		for i, v := range series {
+			fleet[offset+i] += v
+		}
+	}
+	return fleet
+}
+
+// forecastOutlook is the expected reward to the end of the month and over
+// the next 30 days
+type forecastOutlook struct {
+	MonthEnd projection
+	Next30D  projection
+}
+
+func (cfg *config) outlook(series []float64, monthToDate bones, now time.Time) forecastOutlook {
+	f := newForecaster(cfg.Forecast.Model, series)
+
+	// Month end is the completed days so far plus the rest of the month
+	daysInMonth := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()).Day()
+	monthEnd := f.Project(daysInMonth - now.Day() + 1)
+	monthEnd.Expected += monthToDate
+	return forecastOutlook{MonthEnd: monthEnd, Next30D: f.Project(30)}
+}
+
+// HotspotOutlook forecasts a hotspot from its own history
+func (cfg *config) HotspotOutlook(name string, now time.Time) forecastOutlook {
+	monthToDate, _ := cfg.RewardSum(name, 1, now.Day())
+	return cfg.outlook(cfg.dailySeries(name, cfg.Forecast.lookback()), monthToDate, now)
+}
+
+// FleetOutlook forecasts the total of every row
+func (cfg *config) FleetOutlook(now time.Time) forecastOutlook {
+	var monthToDate bones
+	for _, order := range cfg.HsSort {
+		mtd, _ := cfg.RewardSum(order.Name, 1, now.Day())
+		monthToDate += mtd
+	}
+	return cfg.outlook(cfg.fleetSeries(cfg.Forecast.lookback()), monthToDate, now)
+}
+
+func (cfg *config) outlookString(o forecastOutlook) string {
+	return fmt.Sprintf("Forecast: month end %s (±%s) / next 30D %s (±%s)",
+		cfg.rewardToString(o.MonthEnd.Expected), cfg.rewardToString(o.MonthEnd.Band),
+		cfg.rewardToString(o.Next30D.Expected), cfg.rewardToString(o.Next30D.Band))
+}
@@ -7,5 +7,5 @@ require (
 	github.com/getlantern/systray v1.1.0
 	github.com/pkg/browser v0.0.0-20210115035449-ce105d075bb4
 	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2 // indirect
-	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68 // indirect
+	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68
 )
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+// hotspotGroup is a named set of hotspots shown under one menu entry
+type hotspotGroup struct {
+	Name     string        `json:"name"`
+	Hotspots []string      `json:"hotspots"`        // hotspot addresses
+	Split    *revenueSplit `json:"split,omitempty"` // revenue split of hotspots without their own
+}
+
+type groupMenuItem struct {
+	MenuItem *systray.MenuItem
+	Rewards  []*systray.MenuItem // one row per reward window
+	Rows     []*hotspotMenuItem  // hotspot rows nested in the group
+}
+
+// newGroupMenuItem adds a group row to parent, or to the top level of the
+// menu if parent is nil
+func newGroupMenuItem(parent *systray.MenuItem, windows []rewardWindow) *groupMenuItem {
+	var item *systray.MenuItem
+	if parent != nil {
+		item = parent.AddSubMenuItem("Loading...", "")
+	} else {
+		item = systray.AddMenuItem("Loading...", "")
+	}
+	group := &groupMenuItem{MenuItem: item}
+	group.Rewards = addRewardRows(item, windows)
+	return group
+}
+
+func hasGroup(groups []hotspotGroup, name string) bool {
+	for _, group := range groups {
+		if group.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupIndex returns the index of the first group with address, or -1
+func (cfg *config) GroupIndex(address string) int {
+	for g, group := range cfg.Groups {
+		for _, addr := range group.Hotspots {
+			if addr == address {
+				return g
+			}
+		}
+	}
+	return -1
+}
+
+func (cfg *config) groupByName(name string) int {
+	for g, group := range cfg.Groups {
+		if group.Name == name {
+			return g
+		}
+	}
+	return -1
+}
+
+// GroupDiff sums the reward diffs of the hotspots in orders
+func (cfg *config) GroupDiff(orders []sortOrder, days int) rewardDiff {
+	sum := rewardDiff{Days: days}
+	for _, order := range orders {
+		sum = sum.Add(cfg.RewardDiff(order.Name, days))
+	}
+	return sum
+}
+
+// groupStatus is offline when any of the hotspots in orders is, otherwise
+// syncing when any of them is
+func (cfg *config) groupStatus(orders []sortOrder) string {
+	result := statusOnline
+	for _, order := range orders {
+		switch status := cfg.HotspotStatus(order.Name); status {
+		case statusOnline:
+		case statusSyncing:
+			result = statusSyncing
+		default:
+			return status
+		}
+	}
+	return result
+}
+
+func (cfg *config) updateGroup(item *groupMenuItem, name string, orders []sortOrder, now time.Time) {
+	onlineStatus := cfg.groupStatus(orders)
+	r24H := cfg.GroupDiff(orders, 1)
+	setStatus(item.MenuItem, onlineStatus, r24H.Diff())
+	if r24H.CurrentDays == 0 {
+		item.MenuItem.SetTitle(fmt.Sprintf("n/a - %s (%d)", name, len(orders)))
+	} else {
+		item.MenuItem.SetTitle(fmt.Sprintf("%s - %s (%d)", cfg.rewardToString(r24H.Current), name, len(orders)))
+	}
+
+	item.Rewards = syncRewardRows(item.MenuItem, item.Rewards, cfg.RewardWindows)
+	for j, w := range cfg.RewardWindows {
+		d := cfg.GroupDiff(orders, w.Length(now))
+		setStatus(item.Rewards[j], onlineStatus, d.Diff())
+		item.Rewards[j].SetTitle(cfg.rewardDiffString(w.Label(), d))
+	}
+}
@@ -0,0 +1,37 @@
+// File generated by 2goarray v0.1.0 (http://github.com/cratonica/2goarray)
+
+package icon
+
+var StatusSync []byte = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 
+	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x16, 0x00, 0x00, 0x00, 0x16, 
+	0x08, 0x06, 0x00, 0x00, 0x00, 0xc4, 0xb4, 0x6c, 0x3b, 0x00, 0x00, 0x01, 
+	0x2a, 0x49, 0x44, 0x41, 0x54, 0x78, 0xda, 0xb4, 0xd5, 0x3d, 0x4e, 0xc3, 
+	0x40, 0x10, 0xc5, 0xf1, 0xff, 0x8c, 0x42, 0x15, 0x8a, 0x88, 0x92, 0x6a, 
+	0x53, 0xba, 0xcb, 0x11, 0x9c, 0x1b, 0x98, 0x82, 0x86, 0x26, 0x47, 0x08, 
+	0x37, 0xc0, 0xdc, 0x20, 0xdc, 0x20, 0xa9, 0x29, 0xc8, 0x0d, 0x48, 0x4e, 
+	0x40, 0x2a, 0x5c, 0xb2, 0x27, 0x40, 0x06, 0x11, 0x50, 0x14, 0x89, 0x45, 
+	0x36, 0x26, 0x24, 0x96, 0xc3, 0x87, 0xbc, 0x7e, 0x92, 0xcb, 0xf9, 0x69, 
+	0x64, 0x8d, 0xde, 0xb6, 0xd8, 0x93, 0xe5, 0x34, 0xe8, 0x00, 0x03, 0x20, 
+	0x04, 0x7a, 0x80, 0x01, 0x00, 0x6c, 0xf1, 0x8d, 0x81, 0x79, 0x3b, 0x4a, 
+	0x2c, 0x15, 0x91, 0x2a, 0xd0, 0x39, 0xce, 0x45, 0x18, 0x02, 0x1d, 0x7e, 
+	0x89, 0x73, 0x5c, 0x1e, 0x9e, 0x24, 0xf1, 0x8f, 0xf0, 0x72, 0x1a, 0x18, 
+	0xe0, 0x16, 0x30, 0xfc, 0x2f, 0x16, 0xe8, 0x6f, 0x6f, 0xaf, 0x1e, 0x50, 
+	0x80, 0x7c, 0xb6, 0x30, 0x76, 0xe1, 0x1a, 0x68, 0x19, 0xef, 0x6c, 0xe0, 
+	0x97, 0x9b, 0x20, 0xae, 0x89, 0x6e, 0xe3, 0x43, 0x00, 0x29, 0xd6, 0x7f, 
+	0xc0, 0x5f, 0x52, 0xa0, 0xab, 0xef, 0x8e, 0x10, 0xbf, 0xc9, 0xcf, 0x54, 
+	0x55, 0x18, 0xe0, 0x3f, 0xa1, 0x7a, 0xfa, 0xb7, 0xe5, 0xf4, 0x9a, 0x82, 
+	0x8d, 0xd2, 0x50, 0x14, 0xb0, 0x0d, 0xb8, 0x56, 0x81, 0x45, 0x53, 0xf0, 
+	0xac, 0x01, 0x78, 0xac, 0xc0, 0x04, 0x48, 0x7d, 0xaa, 0xab, 0x35, 0x73, 
+	0x6d, 0x47, 0x49, 0x0a, 0x8c, 0x7c, 0xa1, 0xce, 0x11, 0x1f, 0x9d, 0x26, 
+	0x56, 0xf8, 0x2e, 0xf5, 0x3b, 0x0f, 0xa7, 0x67, 0xdb, 0x51, 0xd2, 0x05, 
+	0x50, 0x80, 0x6c, 0xeb, 0xd5, 0x9a, 0x7e, 0xcd, 0x0b, 0xb1, 0x85, 0xc1, 
+	0x06, 0x06, 0xc8, 0xd6, 0xaf, 0x81, 0xe7, 0xb3, 0x99, 0x01, 0x40, 0xd5, 
+	0xd3, 0xc4, 0x67, 0x8d, 0x5e, 0x88, 0x10, 0xff, 0xa5, 0xc9, 0x9c, 0x63, 
+	0xf4, 0xfc, 0x26, 0x57, 0xc7, 0x67, 0xf7, 0x3b, 0x07, 0x20, 0xfb, 0x26, 
+	0x1e, 0xaf, 0x03, 0x73, 0xd0, 0x22, 0x2c, 0x4a, 0xca, 0x94, 0x1e, 0xd3, 
+	0x05, 0x30, 0x7b, 0x7a, 0x95, 0x49, 0x19, 0xfc, 0xca, 0xc7, 0x00, 0xdc, 
+	0xb1, 0x68, 0x2c, 0xd7, 0x20, 0x34, 0x2f, 0x00, 0x00, 0x00, 0x00, 0x49, 
+	0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82, 
+}
+
@@ -0,0 +1,54 @@
+// File generated by 2goarray v0.1.0 (http://github.com/cratonica/2goarray)
+
+package icon
+
+var StatusSyncDown []byte = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 
+	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x16, 0x00, 0x00, 0x00, 0x16, 
+	0x08, 0x06, 0x00, 0x00, 0x00, 0xc4, 0xb4, 0x6c, 0x3b, 0x00, 0x00, 0x01, 
+	0xf4, 0x49, 0x44, 0x41, 0x54, 0x78, 0xda, 0xac, 0xd5, 0xbd, 0x4f, 0x53, 
+	0x51, 0x18, 0xc7, 0xf1, 0xef, 0x39, 0xa1, 0xd8, 0x5a, 0x4c, 0x1a, 0x46, 
+	0xc3, 0x50, 0xc6, 0x3a, 0x31, 0xe9, 0x64, 0x52, 0x26, 0x1d, 0x1c, 0xda, 
+	0xa8, 0x03, 0x75, 0x20, 0x71, 0xf5, 0x05, 0x17, 0x9d, 0x34, 0x14, 0x9d, 
+	0xd4, 0x05, 0x03, 0xc6, 0xd1, 0x32, 0x88, 0x83, 0x03, 0x6c, 0xc6, 0x09, 
+	0xfa, 0x17, 0xc8, 0xc4, 0x1d, 0xbd, 0x03, 0xac, 0x58, 0x5f, 0xb0, 0xb4, 
+	0x35, 0x1c, 0x73, 0x6e, 0xee, 0x25, 0xa7, 0xa7, 0xe7, 0x5c, 0x24, 0xed, 
+	0x2f, 0x21, 0x24, 0xf7, 0x39, 0xe7, 0xd3, 0x93, 0xa7, 0xa7, 0xcf, 0x1d, 
+	0xc3, 0x93, 0xc3, 0xcd, 0x52, 0x01, 0x98, 0x07, 0xca, 0xc0, 0x0c, 0x50, 
+	0x04, 0x00, 0xc2, 0xf8, 0xaf, 0x01, 0x34, 0xf3, 0x95, 0x20, 0xc4, 0x11, 
+	0xe1, 0x02, 0x95, 0xe2, 0x91, 0x10, 0x2c, 0x00, 0x05, 0x4e, 0x89, 0x52, 
+	0x2c, 0x4d, 0x54, 0x83, 0x7a, 0x2a, 0x7c, 0xb8, 0x59, 0x2a, 0x02, 0x5b, 
+	0xc6, 0xe9, 0xfe, 0x37, 0x21, 0x30, 0x6b, 0x9e, 0x5e, 0x8e, 0x00, 0x25, 
+	0xde, 0xb3, 0x15, 0x1b, 0xfd, 0xf0, 0x10, 0xa8, 0x8d, 0x17, 0x4e, 0xe0, 
+	0xdf, 0x1b, 0xa5, 0xfa, 0x90, 0xa8, 0x89, 0x2f, 0x00, 0x88, 0xf8, 0xf8, 
+	0xdf, 0xec, 0x15, 0xbf, 0xda, 0x82, 0x0b, 0x39, 0x95, 0xaa, 0xec, 0x1d, 
+	0x48, 0xa6, 0x26, 0x8f, 0xed, 0xc7, 0x2d, 0x60, 0x5a, 0x1e, 0x2b, 0xca, 
+	0x76, 0x65, 0x77, 0x4f, 0x72, 0xf5, 0xf9, 0x79, 0x5e, 0x6c, 0x9c, 0xf3, 
+	0xa2, 0xcb, 0x9f, 0xc7, 0xa9, 0xad, 0xe4, 0x08, 0xf6, 0xa5, 0x5d, 0x8a, 
+	0xae, 0xa9, 0x94, 0x82, 0x79, 0x1b, 0xad, 0xbd, 0xcd, 0x45, 0x27, 0x6e, 
+	0x34, 0x33, 0x11, 0xe0, 0x42, 0x57, 0xbe, 0x8c, 0xb3, 0xff, 0x5d, 0x30, 
+	0xb7, 0xea, 0xc4, 0xcb, 0xd2, 0xee, 0xed, 0x93, 0x8f, 0xd9, 0x08, 0x4d, 
+	0xa2, 0x01, 0x13, 0x4f, 0x50, 0xb3, 0x65, 0x8f, 0xd7, 0xb3, 0x36, 0x3c, 
+	0xa3, 0x7b, 0xac, 0xec, 0xbe, 0xdd, 0x59, 0xcd, 0x46, 0xff, 0xcd, 0x3c, 
+	0xb8, 0xd6, 0x3d, 0xf9, 0x20, 0x33, 0xba, 0xc7, 0x1f, 0xee, 0x1f, 0x0d, 
+	0xf4, 0x7a, 0x00, 0x4e, 0xf0, 0x1b, 0xaf, 0x73, 0x7d, 0x27, 0x77, 0xc5, 
+	0x87, 0x02, 0x48, 0x20, 0x74, 0x6d, 0x58, 0xbf, 0xd7, 0x4e, 0xbd, 0x15, 
+	0xba, 0xf6, 0xee, 0xae, 0x1b, 0x05, 0x42, 0x09, 0xec, 0xb8, 0x2a, 0x97, 
+	0xa6, 0xfc, 0xb8, 0x7e, 0xa6, 0x6b, 0x7a, 0x8d, 0x27, 0x11, 0xbc, 0xed, 
+	0xab, 0xea, 0x8d, 0x4f, 0x2b, 0xdd, 0x81, 0xe7, 0xcf, 0xaa, 0x9d, 0x34, 
+	0x14, 0xa0, 0x21, 0x81, 0x35, 0xa0, 0xe5, 0x5b, 0x71, 0xeb, 0x4a, 0x8f, 
+	0x97, 0x73, 0x1d, 0x92, 0xbc, 0xaa, 0x1d, 0x71, 0xf3, 0xf2, 0xdf, 0x34, 
+	0x94, 0x4e, 0x8f, 0xa6, 0x88, 0x07, 0xd0, 0x22, 0x50, 0x4f, 0x5b, 0xfc, 
+	0xbe, 0x99, 0x89, 0xbe, 0xcc, 0x87, 0xd7, 0xbb, 0xa7, 0x8d, 0xd1, 0xfa, 
+	0x44, 0x35, 0x58, 0x12, 0xc6, 0x50, 0xff, 0x3a, 0x82, 0x79, 0x11, 0xe6, 
+	0x2b, 0xc1, 0x34, 0x80, 0x04, 0xc8, 0x57, 0x82, 0x56, 0xa7, 0xc7, 0xac, 
+	0xeb, 0x86, 0x9c, 0x05, 0x8d, 0x8d, 0xfe, 0xb1, 0x39, 0x79, 0x3b, 0x08, 
+	0x87, 0xc0, 0xa3, 0xbd, 0xda, 0xf0, 0xbe, 0x9a, 0xe2, 0x31, 0xba, 0x28, 
+	0x44, 0x7a, 0xcf, 0x01, 0x80, 0x96, 0x52, 0x2c, 0xff, 0x6c, 0x8b, 0x37, 
+	0x17, 0x6b, 0xbb, 0x7d, 0x17, 0xc0, 0xfb, 0xd3, 0x3a, 0xf8, 0x54, 0x2a, 
+	0x66, 0xc6, 0x28, 0xc7, 0x43, 0xaa, 0x68, 0xbd, 0x4c, 0x77, 0x80, 0xed, 
+	0x1f, 0x7f, 0xc4, 0x9a, 0x0d, 0x26, 0xf9, 0x37, 0x00, 0xd2, 0x65, 0xcc, 
+	0xde, 0x47, 0xcf, 0x05, 0x82, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 
+	0x44, 0xae, 0x42, 0x60, 0x82, 
+}
+
@@ -0,0 +1,54 @@
+// File generated by 2goarray v0.1.0 (http://github.com/cratonica/2goarray)
+
+package icon
+
+var StatusSyncUp []byte = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 
+	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x16, 0x00, 0x00, 0x00, 0x16, 
+	0x08, 0x06, 0x00, 0x00, 0x00, 0xc4, 0xb4, 0x6c, 0x3b, 0x00, 0x00, 0x01, 
+	0xf4, 0x49, 0x44, 0x41, 0x54, 0x78, 0xda, 0xac, 0x95, 0x3f, 0x68, 0x53, 
+	0x41, 0x1c, 0xc7, 0x3f, 0x77, 0xfd, 0xa3, 0x21, 0x2a, 0xa1, 0x50, 0x10, 
+	0xe9, 0xf0, 0x02, 0x0e, 0xc6, 0xa9, 0x5b, 0x37, 0x4d, 0xb7, 0x8e, 0x11, 
+	0x14, 0x4c, 0x96, 0xe2, 0x56, 0x10, 0x8c, 0x9b, 0x83, 0x83, 0x4f, 0x70, 
+	0xd1, 0x29, 0xa2, 0x8e, 0x45, 0x5d, 0x74, 0x50, 0x30, 0x9b, 0x63, 0x2d, 
+	0x4e, 0x4e, 0x76, 0xea, 0x73, 0x91, 0xbe, 0xa1, 0x5d, 0x1c, 0x6a, 0xac, 
+	0xd6, 0x18, 0x43, 0x73, 0x72, 0x8f, 0x7b, 0xf2, 0xde, 0xe5, 0xde, 0x4b, 
+	0x24, 0x7e, 0xe1, 0x20, 0xfc, 0xf2, 0xbd, 0x0f, 0xbf, 0xf7, 0xe3, 0xee, 
+	0x7b, 0xd3, 0x64, 0xe8, 0xb0, 0x5d, 0x29, 0x01, 0xab, 0x40, 0x15, 0x58, 
+	0x04, 0x3c, 0x00, 0x20, 0x34, 0xeb, 0x19, 0xb0, 0x59, 0xac, 0x05, 0x21, 
+	0x0e, 0x09, 0x17, 0x50, 0x29, 0x6e, 0x0a, 0x41, 0x13, 0x28, 0x31, 0x42, 
+	0x4a, 0x71, 0xf7, 0xc4, 0xa5, 0xc0, 0xcf, 0x05, 0x1f, 0xb6, 0x2b, 0x1e, 
+	0xb0, 0x91, 0xe8, 0x6e, 0x5c, 0x85, 0xc0, 0x72, 0xb2, 0x7b, 0xf9, 0x1f, 
+	0xa0, 0x98, 0x3d, 0x1b, 0x86, 0x91, 0x06, 0x4f, 0x00, 0xb5, 0xe1, 0xa5, 
+	0xbf, 0xe0, 0x1f, 0x6f, 0x2a, 0xfe, 0x28, 0x68, 0xeb, 0xed, 0x2c, 0x4f, 
+	0x37, 0x67, 0xc6, 0x81, 0x37, 0x01, 0x84, 0x69, 0x7f, 0x27, 0xcf, 0xfd, 
+	0xfa, 0xc3, 0x0c, 0xb7, 0x5e, 0x1e, 0x03, 0xe0, 0x7e, 0xbd, 0xc7, 0xe5, 
+	0xa5, 0x7e, 0x9e, 0xbd, 0x03, 0x94, 0xe5, 0x40, 0x51, 0x1d, 0x17, 0x0a, 
+	0x44, 0xbf, 0x75, 0x2d, 0x47, 0xd1, 0x31, 0x95, 0x52, 0xb0, 0x9a, 0xe5, 
+	0xd8, 0xde, 0x95, 0xdc, 0x6b, 0xcf, 0x0e, 0xd5, 0x75, 0x2d, 0xd8, 0x93, 
+	0x79, 0xf0, 0xaa, 0xcc, 0x9a, 0xad, 0x86, 0x36, 0x9e, 0x14, 0xf8, 0xde, 
+	0x1d, 0x3a, 0xea, 0x51, 0xad, 0xfe, 0xb8, 0x90, 0x07, 0x5f, 0x74, 0x82, 
+	0x77, 0xf7, 0x25, 0x6b, 0xeb, 0x6e, 0xa8, 0x0d, 0xd7, 0x5e, 0x87, 0x3c, 
+	0xe9, 0x82, 0x36, 0x1e, 0x15, 0xd8, 0xfb, 0x9a, 0x86, 0x36, 0x57, 0x7e, 
+	0x47, 0xcb, 0x86, 0x6b, 0xaf, 0x0b, 0x2e, 0x81, 0xd4, 0x5d, 0x5f, 0x5b, 
+	0x3f, 0xee, 0x84, 0xde, 0x30, 0xcb, 0x86, 0x6b, 0xaf, 0xde, 0x63, 0x29, 
+	0x94, 0xc0, 0x56, 0xb2, 0xf2, 0xa0, 0xfe, 0x8b, 0x93, 0x05, 0x35, 0x04, 
+	0x8d, 0x65, 0xc3, 0xb5, 0x57, 0xef, 0xb1, 0xc1, 0x53, 0xb7, 0xaf, 0xce, 
+	0x9f, 0x06, 0x56, 0x00, 0x00, 0xe6, 0x4f, 0x29, 0x2e, 0x9c, 0x3b, 0xe2, 
+	0xfd, 0xa7, 0x29, 0xae, 0x5d, 0xec, 0xa7, 0xa0, 0xb1, 0x96, 0xce, 0x1e, 
+	0x71, 0xd0, 0x15, 0x7c, 0xfe, 0x22, 0x79, 0x71, 0xbd, 0xcb, 0xf9, 0x85, 
+	0x81, 0x6d, 0xf1, 0x85, 0xb9, 0x82, 0x3b, 0x76, 0x92, 0xe9, 0xb9, 0x2d, 
+	0xcc, 0x0d, 0x72, 0xaf, 0x99, 0x9e, 0x71, 0xf2, 0xeb, 0x62, 0xf5, 0xfa, 
+	0x94, 0x65, 0xb1, 0x16, 0x74, 0x80, 0x96, 0xfd, 0xe7, 0x28, 0x68, 0x3c, 
+	0x06, 0x47, 0x8c, 0xfa, 0x73, 0x57, 0x82, 0x50, 0x24, 0x42, 0xfd, 0xe3, 
+	0x84, 0x21, 0x04, 0x10, 0x16, 0x6b, 0x41, 0x19, 0x40, 0x02, 0xe8, 0xae, 
+	0x7b, 0x7d, 0x96, 0xed, 0x13, 0xf2, 0xaf, 0x50, 0xc3, 0x48, 0xc7, 0xa6, 
+	0x6e, 0x7f, 0x02, 0x78, 0xb4, 0x57, 0x33, 0x32, 0x9f, 0x26, 0x13, 0xa3, 
+	0x77, 0x84, 0xc0, 0x1f, 0xcd, 0xa3, 0xa3, 0x14, 0xad, 0x83, 0xae, 0x78, 
+	0x78, 0xa6, 0xb1, 0xdd, 0xc9, 0x7c, 0x9a, 0x92, 0xda, 0x7f, 0x55, 0xf1, 
+	0x66, 0xa6, 0xa9, 0x9a, 0x90, 0xf2, 0xac, 0xc7, 0x74, 0x0b, 0x78, 0xf7, 
+	0xed, 0xa7, 0x78, 0x6e, 0x03, 0x63, 0xfd, 0x19, 0x00, 0x2c, 0x90, 0xcd, 
+	0xb2, 0xc2, 0xa1, 0xf4, 0x0e, 0x00, 0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 
+	0x44, 0xae, 0x42, 0x60, 0x82, 
+}
+
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const ipcTimeout = 10 // Seconds
+
+// IPC commands understood by the running app
+const (
+	ipcRefresh = "refresh"
+	ipcReload  = "reload-config"
+	ipcStatus  = "status"
+)
+
+var errAlreadyRunning = errors.New("Helium Systray is already running")
+
+// errLocked is returned by lockFile when another process holds the lock
+var errLocked = errors.New("Lock is held by another process")
+
+// errNotRunning is returned to commands that need the running app
+var errNotRunning = errors.New("Helium Systray isn't running")
+
+// ipcRequest and ipcResponse are sent as a line of JSON each over the
+// instance socket
+type ipcRequest struct {
+	Command string `json:"command"`
+}
+
+type ipcResponse struct {
+	Error  string     `json:"error,omitempty"`
+	Status *appStatus `json:"status,omitempty"`
+}
+
+type appStatus struct {
+	Total    bones           `json:"total_bones"`
+	Price    usd             `json:"price"` // 1e-8 USD
+	Height   int             `json:"height"`
+	Hotspots []hotspotStatus `json:"hotspots"`
+	Alerts   []string        `json:"alerts"`
+}
+
+type hotspotStatus struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Status  string `json:"status"`
+	Today   bones  `json:"today_bones"`
+}
+
+// instanceLock is held for as long as the app runs and released by the OS
+// when it exits
+var instanceLock *os.File
+
+// instanceDir returns the private runtime dir, falling back to a dir of
+// our own in the temp dir with the user ID so users don't share it
+func instanceDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("helium-systray-%d", os.Getuid()))
+}
+
+func instanceSocketPath() string {
+	return filepath.Join(instanceDir(), "helium-systray.sock")
+}
+
+// privateDir creates dir readable only by us, and refuses a dir someone
+// else could have put in place to listen in
+func privateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() || !ownedByUser(info) {
+		return fmt.Errorf("%s isn't a directory of the current user", dir)
+	}
+	return os.Chmod(dir, 0700)
+}
+
+// acquireInstance takes the instance lock and listens on the instance
+// socket, or returns errAlreadyRunning when another instance holds the lock
+func acquireInstance() (net.Listener, error) {
+	dir := instanceDir()
+	if err := privateDir(dir); err != nil {
+		return nil, err
+	}
+
+	lock, err := os.OpenFile(filepath.Join(dir, "helium-systray.lock"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(lock); err != nil {
+		lock.Close()
+		if err == errLocked {
+			return nil, errAlreadyRunning
+		}
+		return nil, err
+	}
+	instanceLock = lock
+
+	// Holding the lock, any socket left behind is from a crashed instance
+	path := instanceSocketPath()
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// serveInstance answers requests from later invocations until ctx is done
+func serveInstance(ctx context.Context, listener net.Listener, handle func(ipcRequest) ipcResponse) {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Println("Instance socket closed:", err)
+			}
+			return
+		}
+		go serveConn(conn, handle)
+	}
+}
+
+func serveConn(conn net.Conn, handle func(ipcRequest) ipcResponse) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ipcTimeout * time.Second))
+
+	var req ipcRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+	json.NewEncoder(conn).Encode(handle(req))
+}
+
+// sendCommand sends a command to the running app and returns its response
+func sendCommand(command string) (ipcResponse, error) {
+	var resp ipcResponse
+	conn, err := net.DialTimeout("unix", instanceSocketPath(), time.Second)
+	if err != nil {
+		return resp, errNotRunning
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ipcTimeout * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(ipcRequest{Command: command}); err != nil {
+		return resp, err
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return resp, err
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// instanceHandler answers IPC commands from the live config and refresher
+func instanceHandler(cfg *config, refresh *refresher) func(ipcRequest) ipcResponse {
+	return func(req ipcRequest) ipcResponse {
+		switch req.Command {
+		case ipcRefresh:
+			refresh.Refresh()
+			return ipcResponse{}
+		case ipcReload:
+			refresh.Reload()
+			return ipcResponse{}
+		case ipcStatus:
+			status := cfg.Status()
+			return ipcResponse{Status: &status}
+		default:
+			return ipcResponse{Error: fmt.Sprintf("Unknown command %q", req.Command)}
+		}
+	}
+}
+
+// Status returns the total, hotspots and alerts shown in the menu
+func (cfg *config) Status() appStatus {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	status := appStatus{
+		Total:    cfg.Total,
+		Price:    cfg.Price,
+		Height:   cfg.Height,
+		Hotspots: []hotspotStatus{},
+		Alerts:   []string{},
+	}
+	for _, order := range cfg.HsSort {
+		status.Hotspots = append(status.Hotspots, hotspotStatus{
+			Name:    cfg.DisplayName(order.Name),
+			Address: cfg.HsMap[order.Name].Address,
+			Status:  cfg.HotspotStatus(order.Name),
+			Today:   order.Reward,
+		})
+	}
+	for _, a := range cfg.Alerts {
+		status.Alerts = append(status.Alerts, a.String())
+	}
+	return status
+}
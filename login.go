@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const loginItemName = "helium-systray"
+
+// loginItem starts the app when the user logs in. Each platform has its own
+// implementation returned by newLoginItem.
+type loginItem interface {
+	Enabled() (bool, error)
+	Enable() error
+	Disable() error
+}
+
+// appExecutable returns the path of the running binary with symlinks resolved
+func appExecutable() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(path)
+}
+
+// setStartAtLogin enables or disables the login item and returns whether
+// it's enabled afterwards
+func setStartAtLogin(item loginItem, enable bool) (bool, error) {
+	var err error
+	if enable {
+		err = item.Enable()
+	} else {
+		err = item.Disable()
+	}
+	if err != nil {
+		enabled, _ := item.Enabled()
+		return enabled, err
+	}
+	return enable, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const launchAgentLabel = "com.wontaeyang.helium-systray"
+
+// launchAgent starts the app from a LaunchAgent in ~/Library/LaunchAgents
+type launchAgent struct {
+	Home string
+	Exec string // path of the binary to start
+}
+
+// newLoginItem ignores systemd, which is only used on Linux
+func newLoginItem(systemd bool) (loginItem, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	exec, err := appExecutable()
+	if err != nil {
+		return nil, err
+	}
+	return &launchAgent{Home: home, Exec: exec}, nil
+}
+
+func (l *launchAgent) plistPath() string {
+	return filepath.Join(l.Home, "Library", "LaunchAgents", launchAgentLabel+".plist")
+}
+
+func (l *launchAgent) Enabled() (bool, error) {
+	_, err := os.Stat(l.plistPath())
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *launchAgent) Enable() error {
+	if err := os.MkdirAll(filepath.Dir(l.plistPath()), 0755); err != nil {
+		return err
+	}
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+</dict>
+</plist>
+`, launchAgentLabel, html.EscapeString(l.Exec))
+	return ioutil.WriteFile(l.plistPath(), []byte(plist), 0644)
+}
+
+func (l *launchAgent) Disable() error {
+	if err := os.Remove(l.plistPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
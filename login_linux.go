@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// xdgLoginItem starts the app from an XDG autostart entry, or from a systemd
+// user unit tied to the graphical session
+type xdgLoginItem struct {
+	ConfigDir string // XDG config home, usually ~/.config
+	Exec      string // path of the binary to start
+	Systemd   bool   // use a systemd user unit instead of an autostart entry
+}
+
+// newLoginItem uses a systemd user unit instead of an XDG autostart entry
+// when systemd is set
+func newLoginItem(systemd bool) (loginItem, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	exec, err := appExecutable()
+	if err != nil {
+		return nil, err
+	}
+	return newXDGLoginItem(configDir, exec, systemd), nil
+}
+
+func newXDGLoginItem(configDir string, exec string, systemd bool) *xdgLoginItem {
+	return &xdgLoginItem{ConfigDir: configDir, Exec: exec, Systemd: systemd}
+}
+
+func (l *xdgLoginItem) desktopPath() string {
+	return filepath.Join(l.ConfigDir, "autostart", loginItemName+".desktop")
+}
+
+func (l *xdgLoginItem) unitPath() string {
+	return filepath.Join(l.ConfigDir, "systemd", "user", loginItemName+".service")
+}
+
+// wantsPath is the link systemctl --user enable would create
+func (l *xdgLoginItem) wantsPath() string {
+	return filepath.Join(l.ConfigDir, "systemd", "user", "graphical-session.target.wants", loginItemName+".service")
+}
+
+func (l *xdgLoginItem) Enabled() (bool, error) {
+	for _, path := range []string{l.desktopPath(), l.wantsPath()} {
+		if _, err := os.Lstat(path); err == nil {
+			return true, nil
+		} else if !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+func (l *xdgLoginItem) Enable() error {
+	// Start from scratch so switching between autostart and systemd doesn't
+	// leave both behind
+	if err := l.Disable(); err != nil {
+		return err
+	}
+
+	if !l.Systemd {
+		return writeLoginFile(l.desktopPath(), fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Helium Systray
+Comment=Helium hotspot rewards in the system tray
+Exec=%s
+Terminal=false
+X-GNOME-Autostart-enabled=true
+`, desktopExec(l.Exec)))
+	}
+
+	err := writeLoginFile(l.unitPath(), fmt.Sprintf(`[Unit]
+Description=Helium Systray
+PartOf=graphical-session.target
+After=graphical-session.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+
+[Install]
+WantedBy=graphical-session.target
+`, systemdExec(l.Exec)))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.wantsPath()), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(l.unitPath(), l.wantsPath())
+}
+
+func (l *xdgLoginItem) Disable() error {
+	for _, path := range []string{l.desktopPath(), l.wantsPath(), l.unitPath()} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeLoginFile(path string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
+
+// desktopExec quotes a path for the Exec key of a desktop entry. The
+// argument is quoted with backslash escapes, and then escaped again as a
+// desktop entry string, with % doubled so it isn't taken as a field code.
+func desktopExec(path string) string {
+	quoted := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`).Replace(path) + `"`
+	return strings.NewReplacer(`\`, `\\`, "%", "%%").Replace(quoted)
+}
+
+// systemdExec quotes a path for ExecStart of a systemd unit, doubling % and
+// $ so they aren't taken as specifiers or variables
+func systemdExec(path string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(path) + `"`
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func exists(t *testing.T, path string) bool {
+	t.Helper()
+	_, err := os.Lstat(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func TestXDGLoginItem(t *testing.T) {
+	dir, err := ioutil.TempDir("", "login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	desktop := filepath.Join(dir, "autostart", "helium-systray.desktop")
+	unit := filepath.Join(dir, "systemd", "user", "helium-systray.service")
+	wants := filepath.Join(dir, "systemd", "user", "graphical-session.target.wants", "helium-systray.service")
+
+	tests := []struct {
+		name    string
+		systemd bool
+		present []string
+		absent  []string
+	}{
+		{"autostart", false, []string{desktop}, []string{unit, wants}},
+		{"switch to systemd", true, []string{unit, wants}, []string{desktop}},
+		{"switch back to autostart", false, []string{desktop}, []string{unit, wants}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := newXDGLoginItem(dir, "/opt/helium systray/helium-systray", tt.systemd)
+			if err := item.Enable(); err != nil {
+				t.Fatal(err)
+			}
+			if enabled, err := item.Enabled(); err != nil || !enabled {
+				t.Fatalf("Enabled() = %v, %v after Enable", enabled, err)
+			}
+			for _, path := range tt.present {
+				if !exists(t, path) {
+					t.Errorf("%s is missing", path)
+				}
+			}
+			for _, path := range tt.absent {
+				if exists(t, path) {
+					t.Errorf("%s was left behind", path)
+				}
+			}
+		})
+	}
+
+	item := newXDGLoginItem(dir, "/opt/helium-systray", false)
+	if err := item.Disable(); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := item.Enabled(); err != nil || enabled {
+		t.Fatalf("Enabled() = %v, %v after Disable", enabled, err)
+	}
+	for _, path := range []string{desktop, unit, wants} {
+		if exists(t, path) {
+			t.Errorf("%s was left behind", path)
+		}
+	}
+
+	// Disabling twice is fine
+	if err := item.Disable(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXDGLoginItemExec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	item := newXDGLoginItem(dir, "/opt/helium systray/bin", false)
+	if err := item.Enable(); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "autostart", "helium-systray.desktop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "\nExec=\"/opt/helium systray/bin\"\n") {
+		t.Errorf("desktop entry has no quoted Exec:\n%s", raw)
+	}
+}
+
+func TestExecQuoting(t *testing.T) {
+	tests := []struct {
+		path    string
+		desktop string
+		systemd string
+	}{
+		{`/usr/bin/helium-systray`, `"/usr/bin/helium-systray"`, `"/usr/bin/helium-systray"`},
+		{`/opt/my app/bin`, `"/opt/my app/bin"`, `"/opt/my app/bin"`},
+		{`/opt/$HOME/bin`, `"/opt/\\$HOME/bin"`, `"/opt/$$HOME/bin"`},
+		{`/opt/a\b`, `"/opt/a\\\\b"`, `"/opt/a\\b"`},
+		{`/opt/"q"`, `"/opt/\\"q\\""`, `"/opt/\"q\""`},
+		{"/opt/`x`", "\"/opt/\\\\`x\\\\`\"", "\"/opt/`x`\""},
+		{`/opt/100%`, `"/opt/100%%"`, `"/opt/100%%"`},
+	}
+	for _, tt := range tests {
+		if got := desktopExec(tt.path); got != tt.desktop {
+			t.Errorf("desktopExec(%q) = %s, want %s", tt.path, got, tt.desktop)
+		}
+		if got := systemdExec(tt.path); got != tt.systemd {
+			t.Errorf("systemdExec(%q) = %s, want %s", tt.path, got, tt.systemd)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package main
+
+import "errors"
+
+// newLoginItem isn't supported on this platform
+func newLoginItem(systemd bool) (loginItem, error) {
+	return nil, errors.New("Start at login isn't supported on this platform")
+}
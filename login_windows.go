@@ -0,0 +1,57 @@
+package main
+
+import (
+	"golang.org/x/sys/windows/registry"
+)
+
+const runKey = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+// runKeyItem starts the app from the current user's Run registry key
+type runKeyItem struct {
+	Exec string // path of the binary to start
+}
+
+// newLoginItem ignores systemd, which is only used on Linux
+func newLoginItem(systemd bool) (loginItem, error) {
+	path, err := appExecutable()
+	if err != nil {
+		return nil, err
+	}
+	return &runKeyItem{Exec: path}, nil
+}
+
+func (r *runKeyItem) Enabled() (bool, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKey, registry.QUERY_VALUE)
+	if err != nil {
+		return false, err
+	}
+	defer key.Close()
+
+	_, _, err = key.GetStringValue(loginItemName)
+	if err == registry.ErrNotExist {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *runKeyItem) Enable() error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKey, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	return key.SetStringValue(loginItemName, `"`+r.Exec+`"`)
+}
+
+func (r *runKeyItem) Disable() error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKey, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	if err := key.DeleteValue(loginItemName); err != registry.ErrNotExist {
+		return err
+	}
+	return nil
+}
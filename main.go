@@ -1,16 +1,21 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
 	"os/exec"
-	"reflect"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
+	_ "time/tzdata" // timezones for systems without a zoneinfo database
 
 	"github.com/getlantern/systray"
 	"github.com/pkg/browser"
@@ -22,24 +27,90 @@ const (
 	donationAccount = "14EfP4nSYnR2giUiv2yrAGhmMwdt9q8zFqW4STqSp3nsnVnPoiD"
 	refreshMinutes  = 15 // Minutes
 	httpTimeout     = 10 // Seconds
+	rewardDays      = 60 // Days of reward history
+	shutdownTimeout = 5  // Seconds
+	spareRewardRows = 2  // Hidden reward rows per menu entry for windows added by a reload
+)
+
+var (
+	// appCtx is cancelled on quit to stop in-flight requests and routines
+	appCtx, appCancel = context.WithCancel(context.Background())
+	appRoutines       sync.WaitGroup
+
+	// headless is set when running a CLI command without the tray
+	headless bool
+
+	// instanceListener takes commands from later invocations, nil when the
+	// instance socket couldn't be created
+	instanceListener net.Listener
 )
 
 type appSettings struct {
-	AccountAddresses []string `json:"account_addresses"`
-	HotspotAddresses []string `json:"hotspot_addresses"`
+	AccountAddresses []string         `json:"account_addresses"`
+	HotspotAddresses []string         `json:"hotspot_addresses"`
+	RewardDays       int              `json:"reward_days,omitempty"`
+	RewardWindows    []rewardWindow   `json:"reward_windows,omitempty"`
+	Timezone         string           `json:"timezone,omitempty"`
+	DayMode          string           `json:"day_mode,omitempty"`
+	SortBy           string           `json:"sort_by,omitempty"`
+	Hotspots         hotspotSettings  `json:"hotspots,omitempty"`
+	Groups           []hotspotGroup   `json:"groups,omitempty"`
+	TitleGroup       string           `json:"title_group,omitempty"`
+	Forecast         forecastSettings `json:"forecast,omitempty"`
+	Anomalies        anomalySettings  `json:"anomalies,omitempty"`
+	SyncBlocks       int              `json:"sync_blocks,omitempty"`
+	Nearby           string           `json:"nearby,omitempty"`
+	Explorer         explorerSettings `json:"explorer,omitempty"`
+	LoginSystemd     bool             `json:"login_systemd,omitempty"`
+}
+
+// hotspotSettings maps hotspot addresses to their display settings
+type hotspotSettings map[string]hotspotSetting
+
+type hotspotSetting struct {
+	Alias  string `json:"alias,omitempty"`  // name shown instead of the hotspot name
+	Hidden bool   `json:"hidden,omitempty"` // leave out of the menu and total
+	Pinned bool   `json:"pinned,omitempty"` // always show at the top
+	Order  int    `json:"order,omitempty"`  // manual order, lower comes first
+
+	Split *revenueSplit `json:"split,omitempty"` // revenue split with the host
+	Cost  *hotspotCost  `json:"cost,omitempty"`  // purchase and operating costs
 }
 
 type hotspotMenuItem struct {
-	MenuItem *systray.MenuItem
-	Status   *systray.MenuItem
-	Scale    *systray.MenuItem
-	R24H     *systray.MenuItem
-	R07D     *systray.MenuItem
-	R30D     *systray.MenuItem
-	Explorer *systray.MenuItem
+	MenuItem  *systray.MenuItem
+	Status    *systray.MenuItem
+	Scale     *systray.MenuItem
+	Anomaly   *systray.MenuItem
+	Fleet     *systray.MenuItem
+	Nearby    *systray.MenuItem
+	Activity  *activityMenuItem
+	Breakdown *breakdownMenuItem
+	Details   *detailsMenuItem
+	Copy      *copyMenuItem
+	Rewards   []*systray.MenuItem // one row per reward window
+	Forecast  *systray.MenuItem
+	ROI       *roiMenuItem
+	Explorers []*systray.MenuItem // one row per explorer link
 }
 
 func main() {
+	// Finder passes a process serial number to apps on older macOS
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-psn_") {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
+	// Only one instance polls the API, later ones talk to it over IPC
+	listener, err := acquireInstance()
+	if err == errAlreadyRunning {
+		fmt.Println(err)
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Println("Failed to listen on instance socket:", err)
+	}
+	instanceListener = listener
+
 	systray.Run(onReady, onExit)
 }
 
@@ -59,90 +130,165 @@ func onReady() {
 	// Setup initial config values
 	cfg := newConfig(appSettings)
 	setAppTitle("Loading summary...")
-	cfg.FetchAllHotspots()
+	if err := cfg.FetchAllHotspots(appCtx); err != nil {
+		handleError(err, "Failed to fetch hotspots")
+	}
 	cfg.SkipHotspotRefresh = true
 
 	// Setup preferences and quit menu items
 	systray.AddSeparator()
+	cfg.AlertsMenuItem = newAlertsMenuItem()
+	cfg.ForecastMenuItem = systray.AddMenuItem("Loading forecast...", "Forecast of all hotspots from trailing rewards")
+	cfg.AnalyticsMenuItem = newAnalyticsMenuItem()
+	refreshNow := systray.AddMenuItem("Refresh now", "Refresh hotspot data")
+	copyFleet := systray.AddMenuItem("Copy fleet summary", "Copy a Markdown table of all hotspots")
+	exportSettlement := systray.AddMenuItem("Export settlement report...", "Save last month's revenue split settlement to Documents")
 	pref := systray.AddMenuItem("Preferences...", "Adjust preferences")
 	displayHNT := pref.AddSubMenuItem("display rewards in HNT", "display rewards in HNT")
 	displayDollars := pref.AddSubMenuItem("display rewards in USD", "display rewards in USD")
+	sortMenu := pref.AddSubMenuItem("Sort by", "Order of hotspot rows")
+	sortItems := make([]*systray.MenuItem, len(sortModes))
+	for i, m := range sortModes {
+		sortItems[i] = sortMenu.AddSubMenuItemCheckbox(m.Title, "Sort hotspots by "+strings.ToLower(m.Title), m.Mode == cfg.SortBy)
+	}
+	login, loginErr := newLoginItem(appSettings.LoginSystemd)
+	loginEnabled := false
+	if loginErr == nil {
+		loginEnabled, loginErr = login.Enabled()
+	}
+	startAtLogin := pref.AddSubMenuItemCheckbox("Start at login", "Start the app when you log in", loginEnabled)
+	if loginErr != nil {
+		handleSoftError(loginErr, "Failed to check start at login")
+		startAtLogin.Disable()
+	}
 	editConfig := pref.AddSubMenuItem("Edit config...", "Edit the JSON config")
+	reloadConfig := pref.AddSubMenuItem("Reload config", "Reload the JSON config")
 
 	donate := systray.AddMenuItem("Support the project with HNT", "Like the app?")
 	mQuit := systray.AddMenuItem("Quit", "Quits this app")
 
 	// Data refresh routine
-	go func() {
-		for {
-			cfg.ClearPreviousData()
-			cfg.GetHNTPrice()
-			cfg.RefreshAllHotspots()
-			cfg.GetHotspotRewards()
-			cfg.SortHotspotsByReward()
-			cfg.UpdateView()
-			cfg.SkipHotspotRefresh = false
-			time.Sleep(time.Duration(refreshMinutes) * time.Minute)
-		}
-	}()
+	refresh := newRefresher(cfg)
+	goRoutine(func() {
+		refresh.Run(appCtx)
+	})
 
-	// Sub menu item routine listening for explorer click
-	go func() {
-		var chans []chan struct{}
-		for _, mi := range cfg.HsMenuItems {
-			chans = append(chans, mi.Explorer.ClickedCh)
-		}
+	// IPC routine answering later invocations
+	if instanceListener != nil {
+		goRoutine(func() {
+			serveInstance(appCtx, instanceListener, instanceHandler(cfg, refresh))
+		})
+	}
 
-		cases := make([]reflect.SelectCase, len(chans))
-		for i, ch := range chans {
-			cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)}
+	// Click handling routine, with every item tied to an action
+	goRoutine(func() {
+		events.Run(appCtx)
+	})
+
+	events.Register(refreshNow, refresh.Refresh)
+	events.Register(copyFleet, func() {
+		if err := cfg.CopyFleetSummary(); err != nil {
+			handleSoftError(err, "Failed to copy to clipboard")
 		}
-		for {
-			chosen, _, ok := reflect.Select(cases)
-			if ok {
-				name := cfg.HsSort[chosen].Name
-				addr := cfg.HsMap[name].Address
-				browser.OpenURL(fmt.Sprintf("https://explorer.helium.com/hotspots/%s", addr))
+	})
+	events.Register(exportSettlement, func() {
+		// Only one export at a time, as they write the same files
+		exportSettlement.Disable()
+		goRoutine(func() {
+			defer exportSettlement.Enable()
+			path, err := cfg.ExportSettlement(appCtx, cfg.LastMonth())
+			if err != nil {
+				handleSoftError(err, "Failed to export settlement")
+			} else {
+				fmt.Println("Settlement report saved to", path)
 			}
-		}
-	}()
+		})
+	})
+	events.Register(displayHNT, func() {
+		cfg.SetConvertToDollars(false)
+	})
+	events.Register(displayDollars, func() {
+		cfg.SetConvertToDollars(true)
+	})
 
-	// click handling routine
-	go func() {
-		for {
-			select {
-			case <-displayHNT.ClickedCh:
-				cfg.ConvertToDollars = false
-				cfg.UpdateView()
-			case <-displayDollars.ClickedCh:
-				cfg.ConvertToDollars = true
-				cfg.UpdateView()
-			case <-editConfig.ClickedCh:
-				var app, filepath string
-				if runtime.GOOS == "windows" {
-					app = "explorer"
-					filepath = "file:///" + appSettingsFullPath()
+	// Sort modes, keeping the checked item in sync with the mode
+	for i, item := range sortItems {
+		mode := sortModes[i].Mode
+		events.Register(item, func() {
+			cfg.SetSortBy(mode)
+			for j, other := range sortItems {
+				if sortModes[j].Mode == mode {
+					other.Check()
 				} else {
-					app = "open"
-					filepath = appSettingsFullPath()
+					other.Uncheck()
 				}
-				cmd := exec.Command(app, filepath)
-				cmd.Output()
-			case <-donate.ClickedCh:
-				browser.OpenURL(fmt.Sprintf("https://explorer.helium.com/accounts/%s", donationAccount))
-			case <-mQuit.ClickedCh:
-				systray.Quit()
-				return
 			}
+		})
+	}
+
+	if login != nil {
+		events.Register(startAtLogin, func() {
+			enabled, err := setStartAtLogin(login, !startAtLogin.Checked())
+			if err != nil {
+				handleSoftError(err, "Failed to change start at login")
+			}
+			if enabled {
+				startAtLogin.Check()
+			} else {
+				startAtLogin.Uncheck()
+			}
+		})
+	}
+	events.Register(editConfig, func() {
+		var app, filepath string
+		if runtime.GOOS == "windows" {
+			app = "explorer"
+			filepath = "file:///" + appSettingsFullPath()
+		} else {
+			app = "open"
+			filepath = appSettingsFullPath()
 		}
-	}()
+		cmd := exec.Command(app, filepath)
+		cmd.Output()
+	})
+	events.Register(reloadConfig, refresh.Reload)
+	events.Register(donate, func() {
+		browser.OpenURL(cfg.AccountURL(donationAccount))
+	})
+	events.Register(mQuit, func() {
+		appCancel()
+		systray.Quit()
+	})
 }
 
 func onExit() {
 	fmt.Println("Requested to quit")
+	appCancel()
+
+	// Wait for background routines to wind down, but don't hang on quit
+	done := make(chan struct{})
+	go func() {
+		appRoutines.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout * time.Second):
+		fmt.Println("Timed out waiting for background routines")
+	}
+
 	fmt.Println("Good bye :(")
 }
 
+// goRoutine runs fn in the background and tracks it for onExit
+func goRoutine(fn func()) {
+	appRoutines.Add(1)
+	go func() {
+		defer appRoutines.Done()
+		fn()
+	}()
+}
+
 func appSettingsFullPath() string {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -176,32 +322,225 @@ func loadAppSettings(path string) (appSettings, error) {
 		return as, errors.New("Invalid config JSON")
 	}
 
+	if _, err := newDayClock(as.Timezone, as.DayMode); err != nil {
+		return as, err
+	}
+	if as.SortBy != "" && !validSortMode(as.SortBy) {
+		return as, errors.New("Invalid sort mode")
+	}
+	if as.TitleGroup != "" && !hasGroup(as.Groups, as.TitleGroup) {
+		return as, errors.New("Title group not found")
+	}
+	for _, hs := range as.Hotspots {
+		if hs.Split != nil && hs.Split.validate() != nil {
+			return as, hs.Split.validate()
+		}
+		if hs.Cost != nil && hs.Cost.validate() != nil {
+			return as, hs.Cost.validate()
+		}
+	}
+	for _, g := range as.Groups {
+		if g.Split != nil && g.Split.validate() != nil {
+			return as, g.Split.validate()
+		}
+	}
+	if err := as.Forecast.validate(); err != nil {
+		return as, err
+	}
+	if err := as.Anomalies.validate(); err != nil {
+		return as, err
+	}
+	if err := validSyncBlocks(as.SyncBlocks); err != nil {
+		return as, err
+	}
+	if err := validNearby(as.Nearby); err != nil {
+		return as, err
+	}
+	if err := as.Explorer.validate(); err != nil {
+		return as, err
+	}
+
 	return as, nil
 }
 
-func newHotspotMenuItem(name string) hotspotMenuItem {
-	item := systray.AddMenuItem(fmt.Sprintf("Loading %v", name), "")
-	return hotspotMenuItem{
+// saveAppSetting writes a setting changed from the menu back to the config.
+// The file is read again and only the value of key is replaced, so edits
+// made since the config was loaded and its formatting aren't lost.
+func saveAppSetting(path string, key string, value interface{}) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return errors.New("Home dir not found")
+	}
+
+	rawSettings, err := ioutil.ReadFile(homeDir + path)
+	if err != nil {
+		return errors.New("Config read failed")
+	}
+	rawValue, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	rawSettings, err = setJSONKey(rawSettings, key, rawValue)
+	if err != nil {
+		return errors.New("Invalid config JSON")
+	}
+	return ioutil.WriteFile(homeDir+path, rawSettings, 0644)
+}
+
+// setJSONKey replaces the value of a top level key of the JSON object in data
+// with value, leaving the rest of data as it is. A missing key is added at
+// the end of the object.
+func setJSONKey(data []byte, key string, value json.RawMessage) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("Not a JSON object")
+	}
+
+	start, end := -1, -1
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		// The last of duplicate keys is the one that's read
+		if tok == key {
+			end = int(dec.InputOffset())
+			start = end - len(raw)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	objectEnd := int(dec.InputOffset()) - 1
+
+	var result []byte
+	if start >= 0 {
+		result = append(result, data[:start]...)
+		result = append(result, value...)
+		return append(result, data[end:]...), nil
+	}
+
+	// Added after the last key, or as the only one
+	body := bytes.TrimRight(data[:objectEnd], " \t\r\n")
+	result = append(result, body...)
+	if body[len(body)-1] != '{' {
+		result = append(result, ',')
+	}
+	result = append(result, "\n  "...)
+	rawKey, _ := json.Marshal(key)
+	result = append(result, rawKey...)
+	result = append(result, ": "...)
+	result = append(result, value...)
+	result = append(result, '\n')
+	return append(result, data[objectEnd:]...), nil
+}
+
+// newHotspotMenuItem adds a hotspot row to parent, or to the top level of
+// the menu if parent is nil
+func newHotspotMenuItem(parent *systray.MenuItem, windows []rewardWindow, links []urlTemplate) *hotspotMenuItem {
+	var item *systray.MenuItem
+	if parent != nil {
+		item = parent.AddSubMenuItem("Loading...", "")
+	} else {
+		item = systray.AddMenuItem("Loading...", "")
+	}
+	row := &hotspotMenuItem{
 		MenuItem: item,
 		Status:   item.AddSubMenuItem("Loading...", "Online status"),
 		Scale:    item.AddSubMenuItem("Loading...", "Reward scale"),
-		R24H:     item.AddSubMenuItem("Loading...", "24 hour reward"),
-		R07D:     item.AddSubMenuItem("Loading...", "7 day reward"),
-		R30D:     item.AddSubMenuItem("Loading...", "30 day reward"),
-		Explorer: item.AddSubMenuItem("Loading...", "Open hotspot in Helium explorer"),
+		Anomaly:  item.AddSubMenuItem("Loading...", "Rewards compared to the hotspot's own history"),
+	}
+	row.Rewards = addRewardRows(item, windows)
+	row.Forecast = item.AddSubMenuItem("Loading...", "Forecast from trailing rewards")
+	row.Fleet = item.AddSubMenuItem("Loading...", "Rewards compared to the fleet median")
+	row.Nearby = item.AddSubMenuItem("Loading...", "Rewards compared to the median of nearby hotspots")
+	row.ROI = newROIMenuItem(item)
+	row.Activity = newActivityMenuItem(item)
+	row.Breakdown = newBreakdownMenuItem(item)
+	row.Details = newDetailsMenuItem(item)
+	row.Copy = newCopyMenuItem(item)
+	row.Explorers = syncExplorerRows(item, nil, links)
+	return row
+}
+
+// unregister drops the actions of a row that no longer shows a hotspot
+func (row *hotspotMenuItem) unregister() {
+	for _, item := range row.Explorers {
+		events.Unregister(item)
+	}
+	for _, item := range row.Details.Rows {
+		events.Unregister(item)
+	}
+	events.Unregister(row.Copy.Address)
+	events.Unregister(row.Copy.URL)
+	events.Unregister(row.Copy.Summary)
+}
+
+// RewardRows returns a reward row for each window
+func (row *hotspotMenuItem) RewardRows(windows []rewardWindow) []*systray.MenuItem {
+	row.Rewards = syncRewardRows(row.MenuItem, row.Rewards, windows)
+	return row.Rewards[:len(windows)]
+}
+
+// addRewardRows adds a row per window to parent, followed by spareRewardRows
+// hidden ones so windows added by a reload stay with the other reward rows
+func addRewardRows(parent *systray.MenuItem, windows []rewardWindow) []*systray.MenuItem {
+	var rows []*systray.MenuItem
+	for _, w := range windows {
+		rows = append(rows, parent.AddSubMenuItem("Loading...", w.Description()))
 	}
+	for i := 0; i < spareRewardRows; i++ {
+		item := parent.AddSubMenuItem("Loading...", "")
+		item.Hide()
+		rows = append(rows, item)
+	}
+	return rows
+}
+
+// syncRewardRows shows a row per window and hides the rest, adding rows to
+// parent when a reload has more windows than there are rows. Those rows end
+// up below the rest of the sub-menu, as items can't be inserted. All rows
+// are returned, the first of them for each window.
+func syncRewardRows(parent *systray.MenuItem, rows []*systray.MenuItem, windows []rewardWindow) []*systray.MenuItem {
+	for len(rows) < len(windows) {
+		rows = append(rows, parent.AddSubMenuItem("Loading...", ""))
+	}
+	for i, item := range rows {
+		if i < len(windows) {
+			item.SetTooltip(windows[i].Description())
+			item.Show()
+		} else {
+			item.Hide()
+		}
+	}
+	return rows
 }
 
 func setAppTitle(msg string) {
-	systray.SetTitle(msg)
+	if !headless {
+		systray.SetTitle(msg)
+	}
 }
 
 func handleSoftError(err error, msg string) {
+	if headless {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", msg, err)
+		return
+	}
+
 	systray.SetTitle(msg)
 	fmt.Println(err)
 }
 
 func handleError(err error, msg string) {
+	if headless {
+		log.Fatalln(err)
+	}
+
 	if msg != "" {
 		systray.SetTitle(msg)
 	} else {
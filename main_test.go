@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestSetJSONKey(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+		err  bool
+	}{
+		{"replaced in place",
+			"{\n    \"timezone\": \"UTC\",\n    \"sort_by\": \"reward\",\n    \"accounts\": []\n}\n",
+			"{\n    \"timezone\": \"UTC\",\n    \"sort_by\": \"name\",\n    \"accounts\": []\n}\n", false},
+		{"compact", `{"sort_by":"reward","b":1}`, `{"sort_by":"name","b":1}`, false},
+		{"nested key kept", `{"hotspots": {"sort_by": 1}, "sort_by": null}`, `{"hotspots": {"sort_by": 1}, "sort_by": "name"}`, false},
+		{"last duplicate", `{"sort_by": "a", "sort_by": "b"}`, `{"sort_by": "a", "sort_by": "name"}`, false},
+		{"added", "{\n  \"timezone\": \"UTC\"\n}\n", "{\n  \"timezone\": \"UTC\",\n  \"sort_by\": \"name\"\n}\n", false},
+		{"added to empty", "{}\n", "{\n  \"sort_by\": \"name\"\n}\n", false},
+		{"not an object", `["sort_by"]`, "", true},
+		{"invalid", `{"sort_by": }`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setJSONKey([]byte(tt.data), "sort_by", []byte(`"name"`))
+			if (err != nil) != tt.err {
+				t.Fatalf("setJSONKey(%q) error = %v, want error %v", tt.data, err, tt.err)
+			}
+			if !tt.err && string(got) != tt.want {
+				t.Errorf("setJSONKey(%q) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+}
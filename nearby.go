@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Areas nearby hotspots are taken from
+const (
+	nearbyHex  = "hex"  // the same H3 hex as the hotspot
+	nearbyCity = "city" // the same city as the hotspot
+)
+
+const (
+	maxNearby     = 25  // nearby hotspots compared per area
+	nearbyMinutes = 360 // minimum time between nearby fetches
+)
+
+func validNearby(mode string) error {
+	switch mode {
+	case "", nearbyHex, nearbyCity:
+		return nil
+	default:
+		return errors.New("Invalid nearby area")
+	}
+}
+
+// nearbyArea is the median reward of the hotspots around ours, for each of
+// the analyticsPeriods
+type nearbyArea struct {
+	Count   int // nearby hotspots compared
+	Medians []bones
+}
+
+// areaKey returns the hex or city of a hotspot, empty when it has none
+func (cfg *config) areaKey(hs hotspot) string {
+	switch cfg.Nearby {
+	case nearbyHex:
+		return hs.LocationHex
+	case nearbyCity:
+		return hs.Geocode.CityID
+	default:
+		return ""
+	}
+}
+
+// GetNearby fetches the rewards of hotspots in the same area as the visible
+// hotspots, at most once every nearbyMinutes
+func (cfg *config) GetNearby(ctx context.Context) error {
+	now := cfg.Clock.Now()
+	if cfg.Nearby == "" || now.Sub(cfg.NearbyFetched) < nearbyMinutes*time.Minute {
+		return nil
+	}
+
+	// Our own hotspots are left out so the area stands on its own
+	tracked := make(map[string]bool)
+	for _, hs := range cfg.HsMap {
+		tracked[hs.Address] = true
+	}
+
+	areas := make(map[string]nearbyArea)
+	for _, hs := range cfg.HsMap {
+		key := cfg.areaKey(hs)
+		if _, done := areas[key]; done || key == "" || cfg.Hotspots[hs.Address].Hidden {
+			continue
+		}
+
+		area, err := cfg.fetchArea(ctx, key, tracked, now)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			handleSoftError(err, "Failed to get nearby hotspots")
+			continue
+		}
+		areas[key] = area
+	}
+
+	cfg.mu.Lock()
+	cfg.NearbyAreas = areas
+	cfg.NearbyFetched = now
+	cfg.mu.Unlock()
+	return nil
+}
+
+func (cfg *config) fetchArea(ctx context.Context, key string, tracked map[string]bool, now time.Time) (nearbyArea, error) {
+	var resp hotspotsResponse
+	var err error
+	if cfg.Nearby == nearbyHex {
+		resp, err = getHexHotspots(ctx, key)
+	} else {
+		resp, err = getCityHotspots(ctx, key)
+	}
+	if err != nil {
+		return nearbyArea{}, err
+	}
+
+	values := make([][]float64, len(analyticsPeriods))
+	for _, hs := range resp.Data {
+		if tracked[hs.Address] {
+			continue
+		}
+		if len(values[0]) == maxNearby {
+			break
+		}
+
+		// The windows end now, so they're never cached
+		for i, days := range analyticsPeriods {
+			total, err := getHotspotRewardTotal(ctx, requestGetUncached, hs.Address, cfg.Clock.WindowStart(now, days), now)
+			if err != nil {
+				return nearbyArea{}, err
+			}
+			values[i] = append(values[i], float64(total.Data.Sum))
+		}
+		if err := cfg.sleep(ctx); err != nil {
+			return nearbyArea{}, err
+		}
+	}
+
+	area := nearbyArea{Count: len(values[0])}
+	for _, v := range values {
+		area.Medians = append(area.Medians, bones(math.Round(median(v))))
+	}
+	return area, nil
+}
+
+// NearbyArea returns the area around a hotspot when it has nearby hotspots
+func (cfg *config) NearbyArea(name string) (nearbyArea, bool) {
+	area, found := cfg.NearbyAreas[cfg.areaKey(cfg.HsMap[name])]
+	return area, found && area.Count > 0
+}
+
+// nearbyString compares a hotspot with the median of its area in each of
+// the analyticsPeriods
+func (cfg *config) nearbyString(name string) string {
+	area, found := cfg.NearbyArea(name)
+	if !found {
+		return "vs nearby median: n/a"
+	}
+
+	result := fmt.Sprintf("vs %d nearby:", area.Count)
+	for i, days := range analyticsPeriods {
+		sep := " "
+		if i > 0 {
+			sep = " / "
+		}
+		reward, n := cfg.RewardSum(name, 0, days)
+		if median := area.Medians[i]; median > 0 && n > 0 {
+			result += fmt.Sprintf("%s%02dD %.0f%%", sep, days, float64(reward)/float64(median)*100)
+		} else {
+			result += fmt.Sprintf("%s%02dD n/a", sep, days)
+		}
+	}
+	return result
+}
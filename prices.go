@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	dateFormat    = "2006-01-02"
+	maxPricePages = 1000 // stop paging oracle prices after this many pages
+)
+
+// priceHistory keeps the average HNT price of completed days on disk
+var priceHistory = newPriceStore()
+
+// priceStore is a disk store of daily average prices keyed by date in the
+// configured timezone, so they line up with reward days. A nil store is
+// valid and never has any prices.
+type priceStore struct {
+	path string
+}
+
+type storedPrices struct {
+	Location string         `json:"location"` // timezone the days were averaged in
+	Prices   map[string]usd `json:"prices"`
+}
+
+func newPriceStore() *priceStore {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	return &priceStore{path: filepath.Join(cacheDir, "helium-systray", "prices.json")}
+}
+
+// Load returns the stored prices, or none when they were averaged in
+// another timezone
+func (s *priceStore) Load(loc *time.Location) map[string]usd {
+	prices := make(map[string]usd)
+	if s == nil {
+		return prices
+	}
+
+	raw, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return prices
+	}
+	var stored storedPrices
+	if err := json.Unmarshal(raw, &stored); err != nil || stored.Location != loc.String() {
+		return prices
+	}
+	for key, price := range stored.Prices {
+		prices[key] = price
+	}
+	return prices
+}
+
+func (s *priceStore) Save(loc *time.Location, prices map[string]usd) error {
+	if s == nil {
+		return nil
+	}
+	return saveJSON(s.path, storedPrices{Location: loc.String(), Prices: prices})
+}
+
+// fetchDailyPrices returns the average HNT price of each day in loc since
+// the given time. Oracle prices are paged newest first, so paging stops at
+// the newest completed day already stored, unless older days are missing.
+func fetchDailyPrices(ctx context.Context, since time.Time, loc *time.Location) (map[string]usd, error) {
+	prices := priceHistory.Load(loc)
+	today := time.Now().In(loc).Format(dateFormat)
+	sinceKey := since.In(loc).Format(dateFormat)
+
+	// Days up to stopAfter are stored already
+	stopAfter := ""
+	if _, stored := prices[sinceKey]; stored {
+		for key := range prices {
+			if key > stopAfter {
+				stopAfter = key
+			}
+		}
+	}
+
+	sums := make(map[string]int64)
+	counts := make(map[string]int64)
+	cursor := ""
+	for page := 0; page < maxPricePages; page++ {
+		resp, err := getPrices(ctx, cursor)
+		if err != nil {
+			return prices, err
+		}
+
+		done := resp.Cursor == ""
+		for _, p := range resp.Data {
+			key := p.Timestamp.In(loc).Format(dateFormat)
+			if key < sinceKey || key <= stopAfter {
+				done = true
+				continue
+			}
+			sums[key] += int64(p.Price)
+			counts[key]++
+		}
+		if done {
+			break
+		}
+		cursor = resp.Cursor
+	}
+
+	for key, sum := range sums {
+		prices[key] = usd(sum / counts[key])
+	}
+
+	// Only completed days are stored, today's average still changes
+	completed := make(map[string]usd)
+	for key, price := range prices {
+		if key < today {
+			completed[key] = price
+		}
+	}
+	return prices, priceHistory.Save(loc, completed)
+}
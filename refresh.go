@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// refresher runs the data refresh cycle in the background. A manual refresh
+// or a config reload cancels the in-flight cycle and starts a new one.
+type refresher struct {
+	cfg     *config
+	refresh chan struct{}
+	reload  chan struct{}
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // cancels the in-flight cycle
+}
+
+func newRefresher(cfg *config) *refresher {
+	return &refresher{
+		cfg:     cfg,
+		refresh: make(chan struct{}, 1),
+		reload:  make(chan struct{}, 1),
+	}
+}
+
+// Run refreshes data every refreshMinutes until ctx is cancelled
+func (r *refresher) Run(ctx context.Context) {
+	for {
+		cycleCtx, cancel := context.WithCancel(ctx)
+		r.mu.Lock()
+		r.cancel = cancel
+		r.mu.Unlock()
+
+		if err := r.cfg.Refresh(cycleCtx); err != nil {
+			fmt.Println("refresh cycle cancelled")
+		}
+		cancel()
+		responseCache.Prune(cacheMaxAge)
+
+		timer := time.NewTimer(time.Duration(refreshMinutes) * time.Minute)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-r.refresh:
+		case <-r.reload:
+			r.reloadConfig(ctx)
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// Refresh cancels the in-flight cycle and starts a new one
+func (r *refresher) Refresh() {
+	r.trigger(r.refresh)
+}
+
+// Reload cancels the in-flight cycle, reloads the config and starts a new cycle
+func (r *refresher) Reload() {
+	r.trigger(r.reload)
+}
+
+func (r *refresher) trigger(ch chan struct{}) {
+	// Cancel before signalling so the new cycle isn't the one cancelled
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.mu.Unlock()
+
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (r *refresher) reloadConfig(ctx context.Context) {
+	setAppTitle("Reloading config...")
+	as, err := loadAppSettings(appSettingsPath)
+	if err != nil {
+		handleSoftError(err, err.Error())
+		return
+	}
+
+	if err := r.cfg.Reload(ctx, as); err != nil && ctx.Err() == nil {
+		handleSoftError(err, "Failed to reload hotspots")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,8 +14,24 @@ var httpClient = http.Client{
 	Timeout: httpTimeout * time.Second,
 }
 
-func requestGet(url string, model interface{}) error {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func requestGet(ctx context.Context, url string, model interface{}) error {
+	return requestGetFrom(ctx, responseCache, url, model)
+}
+
+// requestGetUncached skips the cache for URLs that change with every
+// request, such as ranges ending now, which would never be reused
+func requestGetUncached(ctx context.Context, url string, model interface{}) error {
+	return requestGetFrom(ctx, nil, url, model)
+}
+
+func requestGetFrom(ctx context.Context, cache *httpCache, url string, model interface{}) error {
+	now := time.Now()
+	entry, cached := cache.Load(url)
+	if cached && entry.Fresh(now) {
+		return json.Unmarshal(entry.Body, model)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return err
 	}
@@ -22,52 +39,190 @@ func requestGet(url string, model interface{}) error {
 	// Helium API requires user agent to be set in requests
 	req.Header.Set("User-Agent", "Helium-Systray/1.0")
 
+	// Revalidate stale cache entries instead of downloading them again
+	if cached && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if cached && entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	expires, storable := cacheExpiry(resp.Header, now)
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		entry.Expires = expires
+		if err := cache.Store(entry); err != nil {
+			fmt.Println("Failed to update cache:", err)
+		}
+		return json.Unmarshal(entry.Body, model)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("Request failed with %s", resp.Status)
+	}
 
 	rawBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	return json.Unmarshal(rawBody, model)
+	if err := json.Unmarshal(rawBody, model); err != nil {
+		return err
+	}
+
+	if storable {
+		err := cache.Store(cacheEntry{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Expires:      expires,
+			Body:         rawBody,
+		})
+		if err != nil {
+			fmt.Println("Failed to write cache:", err)
+		}
+	}
+	return nil
+}
+
+func getAccount(ctx context.Context, address string) (accountResponse, error) {
+	path := fmt.Sprintf("https://api.helium.io/v1/accounts/%s", address)
+	var resp accountResponse
+	err := requestGet(ctx, path, &resp)
+	return resp, err
 }
 
-func getAccountHotspots(address string) (hotspotsResponse, error) {
+func getAccountHotspots(ctx context.Context, address string) (hotspotsResponse, error) {
 	path := fmt.Sprintf("https://api.helium.io/v1/accounts/%s/hotspots", address)
 	var resp hotspotsResponse
-	err := requestGet(path, &resp)
+	err := requestGet(ctx, path, &resp)
 	return resp, err
 }
 
-func getHotspot(address string) (hotspotResponse, error) {
+func getHotspot(ctx context.Context, address string) (hotspotResponse, error) {
 	path := fmt.Sprintf("https://api.helium.io/v1/hotspots/%s", address)
 	var resp hotspotResponse
-	err := requestGet(path, &resp)
+	err := requestGet(ctx, path, &resp)
 	return resp, err
 }
 
-func getHotspotRewards(address string) (rewardsResponse, error) {
-	// /rewards/sum?min_time=-60 day&max_time=2021-03-26T06:10:12.251Z&bucket=day
-	now := time.Now()
+// getHexHotspots returns the hotspots asserted in an H3 hex
+func getHexHotspots(ctx context.Context, hex string) (hotspotsResponse, error) {
+	path := fmt.Sprintf("https://api.helium.io/v1/hotspots/hex/%s", hex)
+	var resp hotspotsResponse
+	err := requestGet(ctx, path, &resp)
+	return resp, err
+}
+
+// getCityHotspots returns the first page of hotspots in a city
+func getCityHotspots(ctx context.Context, cityID string) (hotspotsResponse, error) {
+	path := fmt.Sprintf("https://api.helium.io/v1/cities/%s/hotspots", cityID)
+	var resp hotspotsResponse
+	err := requestGet(ctx, path, &resp)
+	return resp, err
+}
+
+// getHotspotRewardTotal returns the rewards of a hotspot between minTime and
+// maxTime as a single sum, using get for the request
+func getHotspotRewardTotal(ctx context.Context, get func(context.Context, string, interface{}) error, address string, minTime time.Time, maxTime time.Time) (rewardTotalResponse, error) {
+	var resp rewardTotalResponse
+	err := get(ctx, rewardTotalPath(address, minTime, maxTime), &resp)
+	return resp, err
+}
+
+func rewardTotalPath(address string, minTime time.Time, maxTime time.Time) string {
 	path := fmt.Sprintf("https://api.helium.io/v1/hotspots/%s/rewards/sum?", address)
 	query := url.Values{
-		"max_time": {now.Format(time.RFC3339)},
-		"min_time": {"-60 day"},
-		"bucket":   {"day"},
+		"min_time": {minTime.UTC().Format(time.RFC3339)},
+		"max_time": {maxTime.UTC().Format(time.RFC3339)},
 	}.Encode()
+	return path + query
+}
 
+func getHotspotRewards(ctx context.Context, address string, minTime time.Time, maxTime time.Time) (rewardsResponse, error) {
 	var resp rewardsResponse
-	err := requestGet(path+query, &resp)
+	err := requestGet(ctx, rewardsPath(address, minTime, maxTime), &resp)
 	return resp, err
 }
 
-func getPrice() (priceResponse, error) {
+func rewardsPath(address string, minTime time.Time, maxTime time.Time) string {
+	// /rewards/sum?min_time=2021-01-25T00:00:00Z&max_time=2021-03-26T06:10:12Z&bucket=day
+	path := fmt.Sprintf("https://api.helium.io/v1/hotspots/%s/rewards/sum?", address)
+	query := url.Values{
+		"min_time": {minTime.UTC().Format(time.RFC3339)},
+		"max_time": {maxTime.UTC().Format(time.RFC3339)},
+		"bucket":   {"day"},
+	}.Encode()
+	return path + query
+}
+
+// getHotspotRewardEntries returns a page of the individual rewards of a
+// hotspot between minTime and maxTime, using get for the request
+func getHotspotRewardEntries(ctx context.Context, get func(context.Context, string, interface{}) error, address string, minTime time.Time, maxTime time.Time, cursor string) (rewardEntriesResponse, error) {
+	path := fmt.Sprintf("https://api.helium.io/v1/hotspots/%s/rewards?", address)
+	query := url.Values{
+		"min_time": {minTime.UTC().Format(time.RFC3339)},
+		"max_time": {maxTime.UTC().Format(time.RFC3339)},
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var resp rewardEntriesResponse
+	err := get(ctx, path+query.Encode(), &resp)
+	return resp, err
+}
+
+func getHotspotWitnesses(ctx context.Context, address string) (witnessesResponse, error) {
+	path := fmt.Sprintf("https://api.helium.io/v1/hotspots/%s/witnesses", address)
+	var resp witnessesResponse
+	err := requestGet(ctx, path, &resp)
+	return resp, err
+}
+
+// getHotspotActivity returns a page of proof of coverage receipts involving
+// the hotspot since minTime, newest first
+func getHotspotActivity(ctx context.Context, address string, minTime time.Time, cursor string) (activityResponse, error) {
+	path := fmt.Sprintf("https://api.helium.io/v1/hotspots/%s/activity?", address)
+	query := url.Values{
+		"filter_types": {"poc_receipts_v1"},
+		"min_time":     {minTime.UTC().Format(time.RFC3339)},
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	var resp activityResponse
+	err := requestGet(ctx, path+query.Encode(), &resp)
+	return resp, err
+}
+
+func getBlockHeight(ctx context.Context) (blockHeightResponse, error) {
+	path := "https://api.helium.io/v1/blocks/height"
+	var resp blockHeightResponse
+	err := requestGet(ctx, path, &resp)
+	return resp, err
+}
+
+func getPrice(ctx context.Context) (priceResponse, error) {
 	path := "https://api.helium.io/v1/oracle/prices/current"
 	var resp priceResponse
-	err := requestGet(path, &resp)
+	err := requestGet(ctx, path, &resp)
+	return resp, err
+}
+
+// getPrices returns a page of oracle prices, newest first
+func getPrices(ctx context.Context, cursor string) (pricesResponse, error) {
+	path := "https://api.helium.io/v1/oracle/prices"
+	if cursor != "" {
+		path += "?" + url.Values{"cursor": {cursor}}.Encode()
+	}
+
+	var resp pricesResponse
+	err := requestGet(ctx, path, &resp)
 	return resp, err
 }
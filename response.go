@@ -43,7 +43,7 @@ type hotspot struct {
 type reward struct {
 	Total     float64   `json:"total"`
 	Timestamp time.Time `json:"timestamp"`
-	Sum       int       `json:"sum"`
+	Sum       bones     `json:"sum"`
 	Stddev    float64   `json:"stddev"`
 	Min       float64   `json:"min"`
 	Median    float64   `json:"median"`
@@ -51,9 +51,21 @@ type reward struct {
 	Avg       float64   `json:"avg"`
 }
 
+// rewardEntry is a single reward paid to a hotspot, with the kind of work
+// it was paid for
+type rewardEntry struct {
+	Account   string    `json:"account"`
+	Amount    bones     `json:"amount"`
+	Block     int       `json:"block"`
+	Gateway   string    `json:"gateway"`
+	Hash      string    `json:"hash"`
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+}
+
 type price struct {
 	Timestamp time.Time `json:"timestamp"`
-	Price     int       `json:"price"`
+	Price     usd       `json:"price"`
 	Block     int       `json:"block"`
 }
 
@@ -74,6 +86,78 @@ type rewardsResponse struct {
 	Data []reward `json:"data"`
 }
 
+type rewardEntriesResponse struct {
+	Data   []rewardEntry `json:"data"`
+	Cursor string        `json:"cursor"`
+}
+
+type blockHeightResponse struct {
+	Data struct {
+		Height int `json:"height"`
+	} `json:"data"`
+}
+
+type rewardTotalResponse struct {
+	Data reward `json:"data"`
+}
+
 type priceResponse struct {
 	Data price `json:"data"`
 }
+
+type account struct {
+	Address    string `json:"address"`
+	Balance    bones  `json:"balance"`
+	DCBalance  int    `json:"dc_balance"`
+	SecBalance bones  `json:"sec_balance"`
+	Nonce      int    `json:"nonce"`
+	Block      int    `json:"block"`
+}
+
+type accountResponse struct {
+	Data account `json:"data"`
+}
+
+type witnessesResponse struct {
+	Data []hotspot `json:"data"`
+}
+
+type pocWitness struct {
+	Gateway       string `json:"gateway"`
+	Timestamp     int64  `json:"timestamp"`
+	IsValid       bool   `json:"is_valid"`
+	InvalidReason string `json:"invalid_reason"`
+}
+
+type pocReceipt struct {
+	Gateway   string `json:"gateway"`
+	Timestamp int64  `json:"timestamp"`
+	Origin    string `json:"origin"`
+}
+
+type pocPathElement struct {
+	Challengee string       `json:"challengee"`
+	Receipt    *pocReceipt  `json:"receipt"`
+	Witnesses  []pocWitness `json:"witnesses"`
+}
+
+// transaction is an entry of hotspot activity. Only proof of coverage
+// receipts are requested, so only their fields are decoded.
+type transaction struct {
+	Type       string           `json:"type"`
+	Hash       string           `json:"hash"`
+	Time       int64            `json:"time"`
+	Height     int              `json:"height"`
+	Challenger string           `json:"challenger"`
+	Path       []pocPathElement `json:"path"`
+}
+
+type activityResponse struct {
+	Data   []transaction `json:"data"`
+	Cursor string        `json:"cursor"`
+}
+
+type pricesResponse struct {
+	Data   []price `json:"data"`
+	Cursor string  `json:"cursor"`
+}
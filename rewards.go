@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+const (
+	dayModeCalendar = "calendar" // days run from midnight to midnight
+	dayModeRolling  = "rolling"  // days are 24 hour periods counted back from now
+)
+
+// rewardHistory keeps completed daily reward buckets on disk so a refresh
+// only has to fetch the current and previous days
+var rewardHistory = newRewardStore()
+
+// dayClock decides where reward days start
+type dayClock struct {
+	Location *time.Location
+	Rolling  bool
+}
+
+// newDayClock uses the system timezone when timezone is empty
+func newDayClock(timezone string, mode string) (dayClock, error) {
+	loc := time.Local
+	if timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return dayClock{}, errors.New("Invalid timezone")
+		}
+	}
+
+	switch mode {
+	case "", dayModeCalendar:
+		return dayClock{Location: loc}, nil
+	case dayModeRolling:
+		return dayClock{Location: loc, Rolling: true}, nil
+	default:
+		return dayClock{}, errors.New("Invalid day mode")
+	}
+}
+
+func (c dayClock) Now() time.Time {
+	return time.Now().In(c.Location)
+}
+
+// Today returns the start of the current calendar day
+func (c dayClock) Today(now time.Time) time.Time {
+	t := now.In(c.Location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.Location)
+}
+
+// WindowStart returns the start of the window of the last days, which
+// includes the current day in calendar mode
+func (c dayClock) WindowStart(now time.Time, days int) time.Time {
+	if c.Rolling {
+		return now.Add(-time.Duration(days) * day)
+	}
+	return c.Today(now).AddDate(0, 0, 1-days)
+}
+
+// DayKey returns the calendar day a bucket belongs to. Buckets are keyed by
+// their middle so a start an hour off midnight still lands on the right day.
+func (c dayClock) DayKey(start time.Time) string {
+	return start.Add(12 * time.Hour).In(c.Location).Format("2006-01-02")
+}
+
+// rewardStore is a disk store of daily reward buckets per hotspot address. A
+// nil store is valid and never has any buckets.
+type rewardStore struct {
+	dir string
+}
+
+type storedRewards struct {
+	Address  string   `json:"address"`
+	Location string   `json:"location"` // timezone the days were bucketed in
+	Buckets  []reward `json:"buckets"`  // newest first
+}
+
+func newRewardStore() *rewardStore {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	return &rewardStore{dir: filepath.Join(cacheDir, "helium-systray", "rewards")}
+}
+
+// Load returns stored buckets keyed by their day. Buckets from another
+// timezone are discarded as their days don't line up.
+func (s *rewardStore) Load(address string, clock dayClock) map[string]reward {
+	buckets := make(map[string]reward)
+	if s == nil {
+		return buckets
+	}
+
+	raw, err := ioutil.ReadFile(s.path(address))
+	if err != nil {
+		return buckets
+	}
+
+	var stored storedRewards
+	if err := json.Unmarshal(raw, &stored); err != nil || stored.Address != address {
+		return buckets
+	}
+	if stored.Location != clock.Location.String() {
+		return buckets
+	}
+
+	for _, r := range stored.Buckets {
+		buckets[clock.DayKey(r.Timestamp)] = r
+	}
+	return buckets
+}
+
+func (s *rewardStore) Save(address string, clock dayClock, buckets []reward) error {
+	if s == nil {
+		return nil
+	}
+
+	return saveJSON(s.path(address), storedRewards{
+		Address:  address,
+		Location: clock.Location.String(),
+		Buckets:  buckets,
+	})
+}
+
+func (s *rewardStore) path(address string) string {
+	return filepath.Join(s.dir, address+".json")
+}
+
+// saveJSON writes v to path through a temp file so readers never see a
+// partial file
+func saveJSON(path string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, raw, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// fetchRewards returns daily reward buckets for the last days, newest first.
+// In calendar mode completed days come from the store and only missing days,
+// the previous day and the current day are requested from the API. Rolling
+// days move with every refresh, so they're always requested in full.
+func fetchRewards(ctx context.Context, address string, days int, clock dayClock) ([]reward, error) {
+	now := clock.Now()
+	if clock.Rolling {
+		var resp rewardsResponse
+		err := requestGetUncached(ctx, rewardsPath(address, now.Add(-time.Duration(days+1)*day), now), &resp)
+		return resp.Data, err
+	}
+
+	today := clock.Today(now)
+	yesterday := today.AddDate(0, 0, -1)
+	start := today.AddDate(0, 0, -days)
+
+	buckets := rewardHistory.Load(address, clock)
+
+	// Backfill completed days missing from the store, one range per gap
+	var gapStart time.Time
+	for d := start; !d.After(yesterday); d = d.AddDate(0, 0, 1) {
+		_, stored := buckets[clock.DayKey(d)]
+		switch {
+		case !stored && d.Before(yesterday) && gapStart.IsZero():
+			gapStart = d
+		case (stored || !d.Before(yesterday)) && !gapStart.IsZero():
+			if err := fetchDays(ctx, requestGet, address, gapStart, d, clock, buckets); err != nil {
+				return nil, err
+			}
+			gapStart = time.Time{}
+		}
+	}
+
+	// The previous day may still be settling, so it's always fetched again.
+	// The range ends now, so it's never cached.
+	if err := fetchDays(ctx, requestGetUncached, address, yesterday, now, clock, buckets); err != nil {
+		return nil, err
+	}
+
+	// Rebuild the window newest first, stopping at the first missing day
+	var result, completed []reward
+	for d := today; !d.Before(start); d = d.AddDate(0, 0, -1) {
+		r, ok := buckets[clock.DayKey(d)]
+		if !ok {
+			break
+		}
+		result = append(result, r)
+		if d.Before(today) {
+			completed = append(completed, r)
+		}
+	}
+
+	if err := rewardHistory.Save(address, clock, completed); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// fetchDays adds the rewards of the calendar days from up to but not
+// including to to buckets, using get for the requests
+func fetchDays(ctx context.Context, get func(context.Context, string, interface{}) error, address string, from time.Time, to time.Time, clock dayClock, buckets map[string]reward) error {
+	for _, r := range clock.dayRanges(from, to) {
+		if r.DST {
+			var resp rewardTotalResponse
+			if err := get(ctx, rewardTotalPath(address, r.Start, r.End), &resp); err != nil {
+				return err
+			}
+			resp.Data.Timestamp = r.Start
+			buckets[clock.DayKey(r.Start)] = resp.Data
+			continue
+		}
+
+		var resp rewardsResponse
+		if err := get(ctx, rewardsPath(address, r.Start, r.End), &resp); err != nil {
+			return err
+		}
+		for _, b := range resp.Data {
+			buckets[clock.DayKey(b.Timestamp)] = b
+		}
+	}
+	return nil
+}
+
+// dayRange is a run of calendar days fetched with one request
+type dayRange struct {
+	Start time.Time
+	End   time.Time
+	DST   bool // a single day that isn't 24 hours long
+}
+
+// dayRanges splits the calendar days from up to but not including to into
+// runs of 24 hour days. API buckets are always 24 hours long, so a day with
+// a DST change is a range of its own and summed exactly, rather than
+// spilling an hour into the next day's bucket or leaving one out.
+func (c dayClock) dayRanges(from time.Time, to time.Time) []dayRange {
+	var ranges []dayRange
+	start := from
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		next := d.AddDate(0, 0, 1)
+		if next.Sub(d) == day {
+			continue
+		}
+		if d.After(start) {
+			ranges = append(ranges, dayRange{Start: start, End: d})
+		}
+		if next.After(to) {
+			next = to
+		}
+		ranges = append(ranges, dayRange{Start: d, End: next, DST: true})
+		start = next
+	}
+	if start.Before(to) {
+		ranges = append(ranges, dayRange{Start: start, End: to})
+	}
+	return ranges
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDayRanges(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	clock := dayClock{Location: loc}
+	date := func(month time.Month, d int) time.Time {
+		return time.Date(2021, month, d, 0, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []dayRange
+	}{
+		{"no DST change", date(time.March, 1), date(time.March, 8),
+			[]dayRange{{Start: date(time.March, 1), End: date(time.March, 8)}}},
+		{"spring forward in the middle", date(time.March, 12), date(time.March, 16),
+			[]dayRange{
+				{Start: date(time.March, 12), End: date(time.March, 14)},
+				{Start: date(time.March, 14), End: date(time.March, 15), DST: true},
+				{Start: date(time.March, 15), End: date(time.March, 16)},
+			}},
+		{"fall back first", date(time.November, 7), date(time.November, 9),
+			[]dayRange{
+				{Start: date(time.November, 7), End: date(time.November, 8), DST: true},
+				{Start: date(time.November, 8), End: date(time.November, 9)},
+			}},
+		{"partial DST day", date(time.March, 13), date(time.March, 14).Add(5 * time.Hour),
+			[]dayRange{
+				{Start: date(time.March, 13), End: date(time.March, 14)},
+				{Start: date(time.March, 14), End: date(time.March, 14).Add(5 * time.Hour), DST: true},
+			}},
+		{"empty", date(time.March, 1), date(time.March, 1), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clock.dayRanges(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("dayRanges() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) || got[i].DST != tt.want[i].DST {
+					t.Errorf("range %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getlantern/systray"
+)
+
+// hotspotCost is what a hotspot cost to buy and costs to run, in USD
+type hotspotCost struct {
+	PurchasePrice float64 `json:"purchase_price"`
+	PurchaseDate  string  `json:"purchase_date"` // YYYY-MM-DD
+	MonthlyOpex   float64 `json:"monthly_opex"`  // power, backhaul and the like
+}
+
+func (c hotspotCost) validate() error {
+	if _, err := time.Parse(dateFormat, c.PurchaseDate); err != nil {
+		return errors.New("Invalid purchase date")
+	}
+	if c.PurchasePrice < 0 || c.MonthlyOpex < 0 {
+		return errors.New("Invalid hotspot cost")
+	}
+	return nil
+}
+
+// roi is the return on a hotspot since it was bought. Earnings are valued at
+// the average price of the day they were earned.
+type roi struct {
+	Since      time.Time
+	EarnedHNT  bones
+	EarnedUSD  usd
+	Cost       usd       // purchase price
+	Opex       usd       // operating costs to date
+	Profit     usd       // earnings less purchase price and operating costs
+	DailyNet   usd       // trailing 30 day earnings per day less operating costs
+	PaidBackOn time.Time // day earnings covered all costs, zero if they don't
+	PaybackOn  time.Time // projected payback at the trailing rate, zero if never
+}
+
+type roiMenuItem struct {
+	MenuItem *systray.MenuItem
+	Earned   *systray.MenuItem
+	Costs    *systray.MenuItem
+	Payback  *systray.MenuItem
+}
+
+func newROIMenuItem(parent *systray.MenuItem) *roiMenuItem {
+	item := parent.AddSubMenuItem("Loading...", "Profit and payback")
+	return &roiMenuItem{
+		MenuItem: item,
+		Earned:   item.AddSubMenuItem("Loading...", "Earnings since purchase"),
+		Costs:    item.AddSubMenuItem("Loading...", "Purchase price and operating costs to date"),
+		Payback:  item.AddSubMenuItem("Loading...", "Payback date"),
+	}
+}
+
+// earliestPurchase returns the earliest purchase date of any hotspot
+func (cfg *config) earliestPurchase() (time.Time, bool) {
+	var earliest time.Time
+	for _, hs := range cfg.Hotspots {
+		if hs.Cost == nil {
+			continue
+		}
+		date, err := time.ParseInLocation(dateFormat, hs.Cost.PurchaseDate, cfg.Clock.Location)
+		if err == nil && (earliest.IsZero() || date.Before(earliest)) {
+			earliest = date
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
+
+// GetPriceHistory fetches daily prices back to the earliest purchase date
+func (cfg *config) GetPriceHistory(ctx context.Context) error {
+	since, found := cfg.earliestPurchase()
+	if !found {
+		return nil
+	}
+
+	prices, err := fetchDailyPrices(ctx, since, cfg.Clock.Location)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		handleSoftError(err, "Failed to get price history")
+	}
+
+	cfg.mu.Lock()
+	cfg.DailyPrices = prices
+	cfg.mu.Unlock()
+	return nil
+}
+
+// ROI works out the return of a hotspot with costs set
+func (cfg *config) ROI(name string, now time.Time) (roi, bool) {
+	cost := cfg.Hotspots[cfg.HsMap[name].Address].Cost
+	if cost == nil {
+		return roi{}, false
+	}
+
+	since, err := time.ParseInLocation(dateFormat, cost.PurchaseDate, cfg.Clock.Location)
+	if err != nil {
+		return roi{}, false
+	}
+
+	r := roi{Since: since, Cost: usdFromFloat(cost.PurchasePrice)}
+	dailyOpex := usdFromFloat(cost.MonthlyOpex).MulDiv(12, 365)
+	sinceKey := since.Format(dateFormat)
+
+	// Walk the days oldest first to find when costs were covered
+	rewards := cfg.HsRewards[name]
+	balance := -r.Cost
+	for i := len(rewards) - 1; i >= 0; i-- {
+		key := cfg.Clock.DayKey(rewards[i].Timestamp)
+		if key < sinceKey {
+			continue
+		}
+
+		price, found := cfg.DailyPrices[key]
+		if !found {
+			price = cfg.Price
+		}
+		earned := rewards[i].Sum.ToUSD(price)
+		r.EarnedHNT += rewards[i].Sum
+		r.EarnedUSD += earned
+		r.Opex += dailyOpex
+
+		balance += earned - dailyOpex
+		switch {
+		case balance < 0:
+			r.PaidBackOn = time.Time{}
+		case r.PaidBackOn.IsZero():
+			r.PaidBackOn, _ = time.ParseInLocation(dateFormat, key, cfg.Clock.Location)
+		}
+	}
+	r.Profit = r.EarnedUSD - r.Cost - r.Opex
+
+	// Project payback from the last 30 completed days
+	trailing, days := cfg.RewardSum(name, 1, 31)
+	if days > 0 {
+		r.DailyNet = trailing.ToUSD(cfg.Price).MulDiv(1, int64(days)) - dailyOpex
+	}
+	if r.Profit < 0 && r.DailyNet > 0 {
+		remaining := int((-r.Profit + r.DailyNet - 1) / r.DailyNet)
+		r.PaybackOn = cfg.Clock.Today(now).AddDate(0, 0, remaining)
+	}
+	return r, true
+}
+
+// Lines formats the return of a hotspot for the menu and the CLI
+func (r roi) Lines(now time.Time) (earned string, costs string, payback string) {
+	earned = fmt.Sprintf("Earned: %s HNT / %s USD since %s", r.EarnedHNT, r.EarnedUSD, r.Since.Format(dateFormat))
+	costs = fmt.Sprintf("Costs: %s USD + %s USD opex", r.Cost, r.Opex)
+
+	switch {
+	case !r.PaidBackOn.IsZero():
+		payback = fmt.Sprintf("Paid back on %s", r.PaidBackOn.Format(dateFormat))
+	case !r.PaybackOn.IsZero():
+		days := int(r.PaybackOn.Sub(now).Hours()/24) + 1
+		payback = fmt.Sprintf("Payback: %s (in %d days)", r.PaybackOn.Format(dateFormat), days)
+	default:
+		payback = "Payback: not at the current rate"
+	}
+	return earned, costs, payback
+}
+
+func (cfg *config) updateROI(item *roiMenuItem, name string, now time.Time) {
+	r, found := cfg.ROI(name, now)
+	if !found {
+		item.MenuItem.Hide()
+		return
+	}
+
+	earned, costs, payback := r.Lines(now)
+	item.MenuItem.SetTitle(fmt.Sprintf("Net profit: %s USD", r.Profit))
+	item.Earned.SetTitle(earned)
+	item.Costs.SetTitle(costs)
+	item.Payback.SetTitle(payback)
+	item.MenuItem.Show()
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// revenueSplit is the share of rewards paid out to a host
+type revenueSplit struct {
+	Percent float64 `json:"percent"` // payee share of rewards
+	Payee   string  `json:"payee"`
+}
+
+func (s revenueSplit) validate() error {
+	if s.Percent < 0 || s.Percent > 100 || s.Payee == "" {
+		return errors.New("Invalid revenue split")
+	}
+	// Shares are worked out in basis points, so a finer percent would be
+	// rounded and no longer match the one in the report
+	if math.Abs(s.Percent*100-math.Round(s.Percent*100)) > 1e-6 {
+		return errors.New("Revenue split percent can't have more than 2 decimals")
+	}
+	return nil
+}
+
+// basisPoints returns the percent in hundredths so shares stay exact
+func (s revenueSplit) basisPoints() int64 {
+	return int64(math.Round(s.Percent * 100))
+}
+
+// Share returns the payee share of amount, rounded to the nearest bone
+func (s revenueSplit) Share(amount bones) bones {
+	return amount.MulDiv(s.basisPoints(), 10000)
+}
+
+// Split returns the revenue split of a hotspot from its own settings, or
+// from its group when it has none
+func (cfg *config) Split(address string) (revenueSplit, bool) {
+	if split := cfg.Hotspots[address].Split; split != nil {
+		return *split, true
+	}
+	if g := cfg.GroupIndex(address); g >= 0 && cfg.Groups[g].Split != nil {
+		return *cfg.Groups[g].Split, true
+	}
+	return revenueSplit{}, false
+}
+
+// NetShare returns what's left of amount after the revenue split of a hotspot
+func (cfg *config) NetShare(name string, amount bones) (bones, bool) {
+	split, found := cfg.Split(cfg.HsMap[name].Address)
+	if !found {
+		return amount, false
+	}
+	return amount - split.Share(amount), true
+}
+
+type settlementLine struct {
+	Payee      string `json:"payee"`
+	Hotspot    string `json:"hotspot"`
+	Address    string `json:"address"`
+	Days       *int   `json:"days"` // days with a reward, nil when unknown
+	Percent    string `json:"percent"`
+	Gross      bones  `json:"gross_bones"`
+	PayeeShare bones  `json:"payee_bones"`
+	Net        bones  `json:"net_bones"`
+
+	Types rewardBreakdown `json:"type_bones,omitempty"` // gross rewards by reward type
+}
+
+type payeeSettlement struct {
+	Payee string           `json:"payee"`
+	Total bones            `json:"total_bones"`
+	Lines []settlementLine `json:"hotspots"`
+}
+
+// settlement is the monthly revenue share owed to each payee
+type settlement struct {
+	Month  string            `json:"month"`
+	Payees []payeeSettlement `json:"payees"`
+}
+
+// monthRewards are the rewards of a hotspot over a calendar month
+type monthRewards struct {
+	Gross   bones
+	Days    int           // days with at least one reward, valid with Entries
+	Entries []rewardEntry // individual rewards, nil when they couldn't be fetched
+}
+
+// daysWithRewards counts the days in loc with at least one of entries
+func daysWithRewards(entries []rewardEntry, loc *time.Location) int {
+	days := make(map[string]bool)
+	for _, e := range entries {
+		days[e.Timestamp.In(loc).Format("2006-01-02")] = true
+	}
+	return len(days)
+}
+
+// settledHotspots returns the addresses of every hotspot with a revenue
+// split by name, hidden ones included as their hosts are still owed a share
+func (cfg *config) settledHotspots() map[string]string {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	result := make(map[string]string)
+	for name, hs := range cfg.HsMap {
+		if _, found := cfg.Split(hs.Address); found {
+			result[name] = hs.Address
+		}
+	}
+	return result
+}
+
+// FetchMonthRewards fetches the rewards of month for every hotspot with a
+// revenue split. The month runs from midnight to midnight in the configured
+// timezone whatever the day mode, and the gross is fetched as one sum so it
+// doesn't depend on how days are bucketed. Any failure to get a gross fails
+// the whole month, since a partial settlement would be wrong.
+func (cfg *config) FetchMonthRewards(ctx context.Context, month time.Time) (map[string]monthRewards, error) {
+	// Exports run alongside refreshes, which may reload the clock
+	cfg.mu.Lock()
+	clock := cfg.Clock
+	cfg.mu.Unlock()
+
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, clock.Location)
+	end := start.AddDate(0, 1, 0)
+
+	// Rewards of the last day may still be settling, so only months that
+	// have been over for a day are cached
+	get := requestGet
+	if clock.Now().Sub(end) < 24*time.Hour {
+		get = requestGetUncached
+	}
+
+	result := make(map[string]monthRewards)
+	for name, address := range cfg.settledHotspots() {
+		total, err := getHotspotRewardTotal(ctx, get, address, start, end)
+		if err != nil {
+			return nil, err
+		}
+		rewards := monthRewards{Gross: total.Data.Sum}
+
+		entries, err := fetchRewardEntries(ctx, get, address, start, end)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			handleSoftError(err, "Failed to get reward breakdown")
+		} else {
+			rewards.Entries = entries
+			rewards.Days = daysWithRewards(entries, clock.Location)
+		}
+		result[name] = rewards
+		if err := cfg.sleep(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Settlement works out the revenue share of month for every hotspot in
+// rewards. Shares are worked out on the monthly sum so payee and net shares
+// add up to the gross rewards to the bone. Lines are broken down by reward
+// type when the individual rewards of the hotspot were fetched.
+func (cfg *config) Settlement(month time.Time, rewards map[string]monthRewards) settlement {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	monthKey := month.Format("2006-01")
+	byPayee := make(map[string]*payeeSettlement)
+	for name, r := range rewards {
+		hs := cfg.HsMap[name]
+		split, found := cfg.Split(hs.Address)
+		if !found {
+			continue
+		}
+
+		line := settlementLine{
+			Payee:   split.Payee,
+			Hotspot: name,
+			Address: hs.Address,
+			Percent: strconv.FormatFloat(split.Percent, 'f', -1, 64),
+			Gross:   r.Gross,
+		}
+		if r.Entries != nil {
+			days := r.Days
+			line.Days = &days
+			line.Types = breakdownOf(r.Entries, month, month.AddDate(0, 1, 0))
+		}
+		line.PayeeShare = split.Share(line.Gross)
+		line.Net = line.Gross - line.PayeeShare
+
+		p, ok := byPayee[split.Payee]
+		if !ok {
+			p = &payeeSettlement{Payee: split.Payee}
+			byPayee[split.Payee] = p
+		}
+		p.Total += line.PayeeShare
+		p.Lines = append(p.Lines, line)
+	}
+
+	result := settlement{Month: monthKey, Payees: []payeeSettlement{}}
+	for _, p := range byPayee {
+		sort.Slice(p.Lines, func(a, b int) bool { return p.Lines[a].Hotspot < p.Lines[b].Hotspot })
+		result.Payees = append(result.Payees, *p)
+	}
+	sort.Slice(result.Payees, func(a, b int) bool { return result.Payees[a].Payee < result.Payees[b].Payee })
+	return result
+}
+
+func (s settlement) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteCSV writes a line per payee and hotspot, with amounts in bones and HNT
+// followed by the gross rewards of each reward type in bones
+func (s settlement) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	header := []string{"month", "payee", "hotspot", "address", "days", "percent",
+		"gross_bones", "payee_bones", "net_bones", "gross_hnt", "payee_hnt", "net_hnt"}
+	for _, rt := range rewardTypes {
+		header = append(header, rt.Type+"_bones")
+	}
+	out.Write(header)
+	for _, p := range s.Payees {
+		for _, l := range p.Lines {
+			days := ""
+			if l.Days != nil {
+				days = strconv.Itoa(*l.Days)
+			}
+			record := []string{s.Month, l.Payee, l.Hotspot, l.Address, days, l.Percent,
+				strconv.FormatInt(int64(l.Gross), 10),
+				strconv.FormatInt(int64(l.PayeeShare), 10),
+				strconv.FormatInt(int64(l.Net), 10),
+				formatFixed(int64(l.Gross), 8),
+				formatFixed(int64(l.PayeeShare), 8),
+				formatFixed(int64(l.Net), 8)}
+			// Types are left blank like days when the breakdown couldn't be fetched
+			for _, rt := range rewardTypes {
+				if l.Types == nil {
+					record = append(record, "")
+				} else {
+					record = append(record, strconv.FormatInt(int64(l.Types[rt.Type]), 10))
+				}
+			}
+			out.Write(record)
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// ExportSettlement saves the settlement of month as CSV and JSON next to
+// the config file and returns the CSV path
+func (cfg *config) ExportSettlement(ctx context.Context, month time.Time) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.New("Home dir not found")
+	}
+
+	rewards, err := cfg.FetchMonthRewards(ctx, month)
+	if err != nil {
+		return "", err
+	}
+
+	s := cfg.Settlement(month, rewards)
+	base := fmt.Sprintf("%s/Documents/helium-settlement-%s", homeDir, s.Month)
+	for ext, write := range map[string]func(io.Writer) error{".csv": s.WriteCSV, ".json": s.WriteJSON} {
+		file, err := os.Create(base + ext)
+		if err != nil {
+			return "", err
+		}
+		err = write(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return base + ".csv", nil
+}
+
+// LastMonth returns the start of the month before today in the configured
+// timezone
+func (cfg *config) LastMonth() time.Time {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	return previousMonth(cfg.Clock.Now())
+}
+
+// previousMonth returns the start of the month before now
+func previousMonth(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, now.Location())
+}
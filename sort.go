@@ -0,0 +1,98 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Sort modes selectable from preferences
+const (
+	sortByReward    = "reward"
+	sortByReward07D = "reward_7d"
+	sortByReward30D = "reward_30d"
+	sortByName      = "name"
+	sortByStatus    = "status"
+	sortByScale     = "scale"
+	sortByManual    = "manual"
+)
+
+// sortModes lists the sort modes in the order shown in preferences
+var sortModes = []struct {
+	Mode  string
+	Title string
+}{
+	{sortByReward, "Today's reward"},
+	{sortByReward07D, "7 day reward"},
+	{sortByReward30D, "30 day reward"},
+	{sortByName, "Name"},
+	{sortByStatus, "Online status"},
+	{sortByScale, "Reward scale"},
+	{sortByManual, "Manual order"},
+}
+
+func validSortMode(mode string) bool {
+	for _, m := range sortModes {
+		if m.Mode == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// SortHotspots orders rows by the selected sort mode. Pinned hotspots come
+// first, ordered among themselves by their manual order.
+func (cfg *config) SortHotspots() {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	sort.SliceStable(cfg.HsSort, func(a, b int) bool {
+		hsA, hsB := cfg.HsMap[cfg.HsSort[a].Name], cfg.HsMap[cfg.HsSort[b].Name]
+		setA, setB := cfg.Hotspots[hsA.Address], cfg.Hotspots[hsB.Address]
+
+		if setA.Pinned != setB.Pinned {
+			return setA.Pinned
+		}
+		if (setA.Pinned || cfg.SortBy == sortByManual) && setA.Order != setB.Order {
+			// Hotspots without an order come after those with one
+			if setA.Order == 0 || setB.Order == 0 {
+				return setB.Order == 0
+			}
+			return setA.Order < setB.Order
+		}
+		return cfg.sortLess(cfg.HsSort[a], cfg.HsSort[b])
+	})
+}
+
+func (cfg *config) sortLess(a sortOrder, b sortOrder) bool {
+	switch cfg.SortBy {
+	case sortByReward07D:
+		rA, _ := cfg.RewardSum(a.Name, 0, 7)
+		rB, _ := cfg.RewardSum(b.Name, 0, 7)
+		return rA > rB
+	case sortByReward30D:
+		rA, _ := cfg.RewardSum(a.Name, 0, 30)
+		rB, _ := cfg.RewardSum(b.Name, 0, 30)
+		return rA > rB
+	case sortByName, sortByManual:
+		return strings.ToLower(cfg.DisplayName(a.Name)) < strings.ToLower(cfg.DisplayName(b.Name))
+	case sortByStatus:
+		onlineA := cfg.HsMap[a.Name].Status.Online == statusOnline
+		onlineB := cfg.HsMap[b.Name].Status.Online == statusOnline
+		if onlineA != onlineB {
+			return onlineA
+		}
+		return a.Reward > b.Reward
+	case sortByScale:
+		return cfg.HsMap[a.Name].RewardScale > cfg.HsMap[b.Name].RewardScale
+	default:
+		return a.Reward > b.Reward
+	}
+}
+
+// DisplayName returns the configured alias of a hotspot or its name
+func (cfg *config) DisplayName(name string) string {
+	if alias := cfg.Hotspots[cfg.HsMap[name].Address].Alias; alias != "" {
+		return alias
+	}
+	return name
+}
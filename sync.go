@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Hotspot states shown in the menu. The API reports online and offline,
+// syncing is worked out from the block height.
+const (
+	statusOnline  = "online"
+	statusSyncing = "syncing"
+)
+
+const defaultSyncBlocks = 500 // blocks behind the chain before a hotspot is syncing
+
+func validSyncBlocks(blocks int) error {
+	if blocks < 0 {
+		return errors.New("Invalid sync_blocks")
+	}
+	return nil
+}
+
+func (cfg *config) syncBlocks() int {
+	if cfg.SyncBlocks == 0 {
+		return defaultSyncBlocks
+	}
+	return cfg.SyncBlocks
+}
+
+func (cfg *config) GetBlockHeight(ctx context.Context) error {
+	resp, err := getBlockHeight(ctx)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	// Without a height hotspots just aren't marked as syncing
+	if err != nil {
+		handleSoftError(err, "Failed to get block height")
+		return nil
+	}
+
+	cfg.mu.Lock()
+	cfg.Height = resp.Data.Height
+	cfg.mu.Unlock()
+	return nil
+}
+
+// SyncLag returns how many blocks a hotspot is behind the chain
+func (cfg *config) SyncLag(name string) (int, bool) {
+	height := cfg.HsMap[name].Status.Height
+	if cfg.Height == 0 || height == 0 {
+		return 0, false
+	}
+	if lag := cfg.Height - height; lag > 0 {
+		return lag, true
+	}
+	return 0, true
+}
+
+// HotspotStatus is the online status of a hotspot, or syncing when it's
+// online but too far behind the chain
+func (cfg *config) HotspotStatus(name string) string {
+	status := cfg.HsMap[name].Status.Online
+	if status != statusOnline {
+		return status
+	}
+	if lag, found := cfg.SyncLag(name); found && lag > cfg.syncBlocks() {
+		return statusSyncing
+	}
+	return status
+}
+
+func (cfg *config) statusString(name string) string {
+	status := cfg.HotspotStatus(name)
+	if lag, _ := cfg.SyncLag(name); lag > 0 {
+		return fmt.Sprintf("Status: %s (%d blocks behind)", status, lag)
+	}
+	return fmt.Sprintf("Status: %s", status)
+}
+
+func syncAlerts(cfg *config, name string) []string {
+	if cfg.HotspotStatus(name) != statusSyncing {
+		return nil
+	}
+	lag, _ := cfg.SyncLag(name)
+	return []string{fmt.Sprintf("syncing, %d blocks behind", lag)}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRewardWindows are shown when the config doesn't set reward_windows
+var defaultRewardWindows = []rewardWindow{
+	{Spec: "1d", Days: 1},
+	{Spec: "7d", Days: 7},
+	{Spec: "30d", Days: 30},
+}
+
+// rewardWindow is a reward comparison period such as "7d", "mtd" or "ytd".
+// Each window is compared against the period of equal length before it.
+type rewardWindow struct {
+	Spec string // as written in the config
+	Days int    // fixed length in days, 0 for to-date windows
+}
+
+func parseRewardWindow(spec string) (rewardWindow, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	switch spec {
+	case "mtd", "ytd":
+		return rewardWindow{Spec: spec}, nil
+	}
+
+	days, err := strconv.Atoi(strings.TrimSuffix(spec, "d"))
+	if err != nil || !strings.HasSuffix(spec, "d") || days < 1 {
+		return rewardWindow{}, fmt.Errorf("Invalid reward window %q", spec)
+	}
+	return rewardWindow{Spec: spec, Days: days}, nil
+}
+
+func (w *rewardWindow) UnmarshalJSON(data []byte) error {
+	var spec string
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+
+	parsed, err := parseRewardWindow(spec)
+	if err != nil {
+		return err
+	}
+	*w = parsed
+	return nil
+}
+
+func (w rewardWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.Spec)
+}
+
+// Length returns the number of daily buckets in the window, counting the
+// current day
+func (w rewardWindow) Length(now time.Time) int {
+	switch w.Spec {
+	case "mtd":
+		return now.Day()
+	case "ytd":
+		return now.YearDay()
+	default:
+		return w.Days
+	}
+}
+
+func (w rewardWindow) Label() string {
+	switch {
+	case w.Spec == "mtd":
+		return "MTD"
+	case w.Spec == "ytd":
+		return "YTD"
+	case w.Days == 1:
+		return "24H"
+	default:
+		return fmt.Sprintf("%02dD", w.Days)
+	}
+}
+
+func (w rewardWindow) Description() string {
+	switch {
+	case w.Spec == "mtd":
+		return "Month to date reward"
+	case w.Spec == "ytd":
+		return "Year to date reward"
+	case w.Days == 1:
+		return "24 hour reward"
+	default:
+		return fmt.Sprintf("%d day reward", w.Days)
+	}
+}